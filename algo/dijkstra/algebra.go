@@ -0,0 +1,216 @@
+package dijkstra
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"math"
+)
+
+var (
+	ErrReliabilityScale = errors.New("Reliability scale must be positive")
+	ErrReliabilityCost  = errors.New("Edge cost is outside the reliability scale")
+)
+
+// Algebra defines how a path is weighed, turning the Dijkstra search into
+// other optimal path problems. Extending a path by an edge must never make
+// it better, otherwise the search isn't exact.
+type Algebra interface {
+	// Identity is the weight of the empty path at the source.
+	Identity() float64
+	// Extend returns the weight of a path extended by an edge.
+	Extend(weight float64, e Edge) float64
+	// Better reports whether weight a is strictly preferable to b.
+	Better(a float64, b float64) bool
+}
+
+type shortest struct{}
+
+func (shortest) Identity() float64                     { return 0 }
+func (shortest) Extend(weight float64, e Edge) float64 { return weight + float64(e.Cost) }
+func (shortest) Better(a float64, b float64) bool      { return a < b }
+
+type widest struct{}
+
+func (widest) Identity() float64                     { return math.Inf(1) }
+func (widest) Extend(weight float64, e Edge) float64 { return math.Min(weight, float64(e.Cost)) }
+func (widest) Better(a float64, b float64) bool      { return a > b }
+
+type reliable struct {
+	scale float64
+}
+
+func (reliable) Identity() float64                       { return 1 }
+func (r reliable) Extend(weight float64, e Edge) float64 { return weight * float64(e.Cost) / r.scale }
+func (reliable) Better(a float64, b float64) bool        { return a > b }
+
+// check rejects costs which aren't probabilities
+func (r reliable) check(e Edge) error {
+	if e.Cost < 0 || float64(e.Cost) > r.scale {
+		return ErrReliabilityCost
+	}
+	return nil
+}
+
+// edgeChecker is implemented by algebras which take only some costs
+type edgeChecker interface {
+	check(e Edge) error
+}
+
+var (
+	// Shortest minimizes the sum of edge costs, like Dijkstra.
+	Shortest Algebra = shortest{}
+	// Widest maximizes the smallest edge cost on the path, that is the
+	// bottleneck bandwidth when costs are capacities.
+	Widest Algebra = widest{}
+)
+
+// MostReliable maximizes the product of edge success probabilities, where
+// an edge with cost c succeeds with probability c/scale. Search fails with
+// ErrReliabilityCost on costs outside [0, scale]. The scale must be positive,
+// otherwise ErrReliabilityScale is returned.
+func MostReliable(scale int) (Algebra, error) {
+	if scale <= 0 {
+		return nil, ErrReliabilityScale
+	}
+	return reliable{scale: float64(scale)}, nil
+}
+
+// WeightedPath holds the best paths found by Search and their weights.
+type WeightedPath struct {
+	source  int
+	weight  []float64
+	reached []bool
+	prev    []int
+	// recorded by WithPredecessors only
+	preds [][]int
+}
+
+// BuildPath returns the best path to target, from target back to source.
+func (p *WeightedPath) BuildPath(target int) []int {
+	return buildPath(p.prev, target)
+}
+
+// Weight returns the weight of the best path to target, and false if
+// target is unreachable.
+func (p *WeightedPath) Weight(target int) (float64, bool) {
+	return p.weight[target], p.reached[target]
+}
+
+// Predecessors returns every vertex preceding target on a best path, see
+// Path.Predecessors.
+func (p *WeightedPath) Predecessors(target int) []int {
+	path := &Path{prev: p.prev, preds: p.preds}
+	return path.Predecessors(target)
+}
+
+// Search finds the best paths from source to every vertex of g, as
+// weighed by the algebra. It runs the loop of Dijkstra, which is Search
+// specialized for integer costs, on float64 weights. Of the options it
+// honors WithPredecessors, WithProgress, WithVisitor and the restrictions;
+// the others fail with ErrUnsupportedOption.
+func Search(g Interface, source int, a Algebra, opts ...Option) (*WeightedPath, error) {
+	return SearchContext(context.Background(), g, source, a, opts...)
+}
+
+// SearchContext is Search which gives up with ctx.Err() once the context
+// is done.
+func SearchContext(ctx context.Context, g Interface, source int, a Algebra, opts ...Option) (*WeightedPath, error) {
+	n := g.VertexCount()
+	if source < 0 || source >= n {
+		return nil, ErrVertexRange
+	}
+	o := newOptions(opts)
+	if err := o.only(optPredecessors | optProgress | optVisitor | optRestrictions); err != nil {
+		return nil, err
+	}
+	g, err := o.view(g)
+	if err != nil {
+		return nil, err
+	}
+	m, err := newMonitor(ctx, o)
+	if err != nil {
+		return nil, err
+	}
+	s := newSearch(n, source, a.Identity(), algebraWeights{algebra: a}, o, roundWeight)
+	if v, ok := o.visitor.(WeightVisitor); ok {
+		s.settle = v.OnSettleWeight
+	}
+	if c, ok := a.(edgeChecker); ok {
+		s.weighing.check = c
+	}
+	if err := s.run(m, g, source, &weightQueue{algebra: a}); err != nil {
+		return nil, err
+	}
+	return &WeightedPath{source: source, weight: s.weight, reached: s.reached, prev: s.prev, preds: s.preds}, nil
+}
+
+// roundWeight is the int form of a weight a Visitor is told, UndefDist for
+// weights out of the int range
+func roundWeight(weight float64) int {
+	// NaN compares false too
+	if !(math.Abs(weight) < float64(maxInt)) {
+		return UndefDist
+	}
+	return int(math.Round(weight))
+}
+
+// algebraWeights runs the search on the weights of an algebra
+type algebraWeights struct {
+	algebra Algebra
+	check   edgeChecker
+}
+
+func (w algebraWeights) relax(weight float64, e Edge, old float64, reached bool) (float64, bool, error) {
+	if w.check != nil {
+		if err := w.check.check(e); err != nil {
+			return 0, false, err
+		}
+	}
+	alt := w.algebra.Extend(weight, e)
+	return alt, !reached || w.algebra.Better(alt, old), nil
+}
+
+type weightItem struct {
+	vertex int
+	weight float64
+}
+
+// weightQueue is a heap of vertexes with the best weight on top
+type weightQueue struct {
+	algebra Algebra
+	items   []weightItem
+}
+
+func (q *weightQueue) Len() int {
+	return len(q.items)
+}
+
+func (q *weightQueue) Less(i, j int) bool {
+	return q.algebra.Better(q.items[i].weight, q.items[j].weight)
+}
+
+func (q *weightQueue) Swap(i, j int) {
+	q.items[i], q.items[j] = q.items[j], q.items[i]
+}
+
+func (q *weightQueue) Push(x interface{}) {
+	q.items = append(q.items, x.(weightItem))
+}
+
+func (q *weightQueue) Pop() interface{} {
+	item := q.items[len(q.items)-1]
+	q.items = q.items[:len(q.items)-1]
+	return item
+}
+
+func (q *weightQueue) push(vertex int, weight float64) {
+	heap.Push(q, weightItem{vertex: vertex, weight: weight})
+}
+
+func (q *weightQueue) pop() int {
+	if q.Len() == 0 {
+		return Undef
+	}
+	return heap.Pop(q).(weightItem).vertex
+}
@@ -0,0 +1,108 @@
+package dijkstra_test
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	algo "github.com/octo47/gomisc/algo/dijkstra"
+	"github.com/octo47/gomisc/algo/dijkstra/graphgen"
+)
+
+func TestSearchWidest(t *testing.T) {
+	// bandwidth: the direct link is thin, the detour is thick
+	graph := algo.NewUndirectedGraph()
+	graph.AddVertexes(5)
+	graph.AddEdge(0, 4, 10)
+	graph.AddEdge(0, 1, 100)
+	graph.AddEdge(1, 2, 40)
+	graph.AddEdge(2, 4, 60)
+	graph.AddEdge(1, 3, 50)
+	graph.AddEdge(3, 4, 45)
+
+	path, err := algo.Search(graph, 0, algo.Widest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w, ok := path.Weight(4); !ok || w != 45 {
+		t.Error("Wrong bottleneck", w, ok)
+	}
+	if route := path.BuildPath(4); !reflect.DeepEqual(route, []int{4, 3, 1, 0}) {
+		t.Error("Wrong widest path", route)
+	}
+}
+
+func TestSearchMostReliable(t *testing.T) {
+	// link success probabilities in percent
+	graph := algo.NewDirectedGraph()
+	graph.AddVertexes(4)
+	graph.AddEdge(0, 3, 50)
+	graph.AddEdge(0, 1, 90)
+	graph.AddEdge(1, 3, 80)
+	graph.AddEdge(0, 2, 99)
+	graph.AddEdge(2, 3, 70)
+
+	reliable, err := algo.MostReliable(100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path, err := algo.Search(graph, 0, reliable)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w, _ := path.Weight(3); math.Abs(w-0.72) > 1e-9 {
+		t.Error("Wrong reliability", w)
+	}
+	if route := path.BuildPath(3); !reflect.DeepEqual(route, []int{3, 1, 0}) {
+		t.Error("Wrong most reliable path", route)
+	}
+
+	if _, err := algo.MostReliable(0); err != algo.ErrReliabilityScale {
+		t.Error("Zero scale accepted", err)
+	}
+	graph.AddEdge(3, 1, 101)
+	if _, err := algo.Search(graph, 0, reliable); err != algo.ErrReliabilityCost {
+		t.Error("Probability above one accepted", err)
+	}
+}
+
+func TestSearchShortestAgreesWithDijkstra(t *testing.T) {
+	graph := graphgen.ErdosRenyi(200, 0.03, 50, 7)
+	want, _ := graph.Dijkstra(0)
+	got, err := algo.Search(graph, 0, algo.Shortest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for v := 0; v < graph.VertexCount(); v++ {
+		w, ok := got.Weight(v)
+		if cost := want.PathCost(v); ok != (cost != algo.UndefDist) || (ok && int(w) != cost) {
+			t.Fatal("Search disagrees with Dijkstra", v, w, cost)
+		}
+	}
+}
+
+func TestSearchOptions(t *testing.T) {
+	// two equally wide ways from 0 to 3
+	graph := algo.NewDirectedGraph()
+	graph.AddVertexes(4)
+	graph.AddEdge(0, 1, 5)
+	graph.AddEdge(0, 2, 5)
+	graph.AddEdge(1, 3, 5)
+	bypass := graph.AddEdge(2, 3, 5)
+
+	path, err := algo.Search(graph, 0, algo.Widest, algo.WithPredecessors())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if preds := path.Predecessors(3); len(preds) != 2 {
+		t.Error("Wrong predecessors", preds)
+	}
+	path, _ = algo.Search(graph, 0, algo.Widest, algo.WithForbiddenEdges(bypass))
+	if preds := path.Predecessors(3); !reflect.DeepEqual(preds, []int{1}) {
+		t.Error("Forbidden edge used", preds)
+	}
+
+	if _, err := algo.Search(graph, 0, algo.Widest, algo.WithQueue(algo.QueueDial)); err != algo.ErrUnsupportedOption {
+		t.Error("Option silently ignored", err)
+	}
+}
@@ -0,0 +1,127 @@
+package dijkstra_test
+
+import (
+	"fmt"
+	"testing"
+
+	algo "github.com/octo47/gomisc/algo/dijkstra"
+	"github.com/octo47/gomisc/algo/dijkstra/graphgen"
+)
+
+var benchSizes = []int{100, 1000, 5000}
+
+func benchmarkDijkstra(b *testing.B, g *algo.Graph) {
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := g.Dijkstra(0); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkDijkstraGrid(b *testing.B) {
+	for _, n := range benchSizes {
+		side := 1
+		for side*side < n {
+			side++
+		}
+		g := graphgen.Grid(side, side, 0.2, 1)
+		b.Run(fmt.Sprint(side*side), func(b *testing.B) { benchmarkDijkstra(b, g) })
+	}
+}
+
+func BenchmarkDijkstraErdosRenyi(b *testing.B) {
+	for _, n := range benchSizes {
+		g := graphgen.ErdosRenyi(n, 8/float64(n), 100, 1)
+		b.Run(fmt.Sprint(n), func(b *testing.B) { benchmarkDijkstra(b, g) })
+	}
+}
+
+func BenchmarkDijkstraBarabasiAlbert(b *testing.B) {
+	for _, n := range benchSizes {
+		g := graphgen.BarabasiAlbert(n, 3, 100, 1)
+		b.Run(fmt.Sprint(n), func(b *testing.B) { benchmarkDijkstra(b, g) })
+	}
+}
+
+func BenchmarkDijkstraGeometric(b *testing.B) {
+	for _, n := range benchSizes {
+		g, _ := graphgen.RandomGeometric(n, 0.05, 1)
+		b.Run(fmt.Sprint(n), func(b *testing.B) { benchmarkDijkstra(b, g) })
+	}
+}
+
+func BenchmarkDijkstraComplete(b *testing.B) {
+	for _, n := range []int{50, 200} {
+		g := graphgen.Complete(n, 100, 1)
+		b.Run(fmt.Sprint(n), func(b *testing.B) { benchmarkDijkstra(b, g) })
+	}
+}
+
+func BenchmarkSearcher(b *testing.B) {
+	for _, n := range benchSizes {
+		g := graphgen.ErdosRenyi(n, 8/float64(n), 100, 1)
+		b.Run(fmt.Sprint(n), func(b *testing.B) {
+			s := algo.NewSearcher(g)
+			s.Dijkstra(0)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := s.Dijkstra(i % n); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkDijkstraQueues(b *testing.B) {
+	grid := graphgen.Grid(100, 100, 0.2, 1)
+	random := graphgen.ErdosRenyi(5000, 8/5000.0, 100, 1)
+	geometric, _ := graphgen.RandomGeometric(5000, 0.03, 1)
+	for _, g := range []struct {
+		name  string
+		graph *algo.Graph
+	}{{"Grid", grid}, {"ErdosRenyi", random}, {"Geometric", geometric}} {
+		for _, q := range []struct {
+			name  string
+			queue algo.Queue
+		}{
+			{"Linear", algo.QueueLinear},
+			{"BinaryHeap", algo.QueueBinaryHeap},
+			{"Dial", algo.QueueDial},
+			{"Radix", algo.QueueRadix},
+		} {
+			graph, queue := g.graph, q.queue
+			b.Run(g.name+"/"+q.name, func(b *testing.B) {
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					if _, err := graph.Dijkstra(0, algo.WithQueue(queue)); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkBetweenness(b *testing.B) {
+	g := graphgen.BarabasiAlbert(1000, 3, 100, 1)
+	for _, workers := range []int{1, 4} {
+		b.Run(fmt.Sprint("workers-", workers), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := g.Betweenness(algo.WithWorkers(workers)); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+	b.Run("sampled-100", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := g.Betweenness(algo.WithSampling(100, 1)); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
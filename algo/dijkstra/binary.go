@@ -0,0 +1,369 @@
+package dijkstra
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"io"
+	"math"
+	"os"
+)
+
+// Binary graph file layout, all numbers little endian:
+//
+//	header   magic, format version, flags, vertex and edge counts,
+//	         cost bounds and the CRC-32C of the whole file, taken with
+//	         the CRC field zeroed
+//	offsets  vertexes+1 uint64, edges of vertex v are offsets[v]..offsets[v+1]
+//	targets  edges uint32
+//	costs    edges int32, padded to 8 bytes
+//	coords   vertexes pairs of float64, when flagCoords is set
+const (
+	binaryMagic   = "DIJKSTRA"
+	binaryVersion = 2
+	headerSize    = 48
+	flagCoords    = 1
+	// targets are uint32, and the counts must keep the sizes computed from
+	// them in range
+	maxBinaryVertexes = 1 << 32
+	maxBinaryEdges    = 1 << 56
+)
+
+var (
+	ErrBadFormat     = errors.New("Not a binary graph file")
+	ErrFormatVersion = errors.New("Unsupported binary graph format version")
+	ErrChecksum      = errors.New("Binary graph file is corrupted")
+	ErrBadGraph      = errors.New("Binary graph file has an invalid edge list")
+	ErrCostRange     = errors.New("Edge cost or target doesn't fit the binary format")
+	ErrCoordCount    = errors.New("Coordinates must be given for every vertex")
+)
+
+var castagnoli = crc32.MakeTable(crc32.Castagnoli)
+
+// Coord is a vertex position.
+type Coord struct {
+	X float64
+	Y float64
+}
+
+type binaryHeader struct {
+	version  uint32
+	flags    uint32
+	vertexes uint64
+	edges    uint64
+	minCost  int32
+	maxCost  int32
+	checksum uint32
+}
+
+func (h *binaryHeader) encode() []byte {
+	b := make([]byte, headerSize)
+	copy(b, binaryMagic)
+	binary.LittleEndian.PutUint32(b[8:], h.version)
+	binary.LittleEndian.PutUint32(b[12:], h.flags)
+	binary.LittleEndian.PutUint64(b[16:], h.vertexes)
+	binary.LittleEndian.PutUint64(b[24:], h.edges)
+	binary.LittleEndian.PutUint32(b[32:], uint32(h.minCost))
+	binary.LittleEndian.PutUint32(b[36:], uint32(h.maxCost))
+	binary.LittleEndian.PutUint32(b[40:], h.checksum)
+	return b
+}
+
+func decodeHeader(b []byte) (*binaryHeader, error) {
+	if len(b) < headerSize || string(b[:8]) != binaryMagic {
+		return nil, ErrBadFormat
+	}
+	h := &binaryHeader{
+		version:  binary.LittleEndian.Uint32(b[8:]),
+		flags:    binary.LittleEndian.Uint32(b[12:]),
+		vertexes: binary.LittleEndian.Uint64(b[16:]),
+		edges:    binary.LittleEndian.Uint64(b[24:]),
+		minCost:  int32(binary.LittleEndian.Uint32(b[32:])),
+		maxCost:  int32(binary.LittleEndian.Uint32(b[36:])),
+		checksum: binary.LittleEndian.Uint32(b[40:]),
+	}
+	if h.version != binaryVersion {
+		return nil, ErrFormatVersion
+	}
+	if h.vertexes > maxBinaryVertexes || h.edges > maxBinaryEdges {
+		return nil, ErrBadFormat
+	}
+	return h, nil
+}
+
+// bodySize returns the expected size of everything after the header. The
+// decoded counts are capped, so it doesn't overflow.
+func (h *binaryHeader) bodySize() uint64 {
+	size := 8*(h.vertexes+1) + 4*h.edges + 4*h.edges
+	size = (size + 7) &^ 7
+	if h.flags&flagCoords != 0 {
+		size += 16 * h.vertexes
+	}
+	return size
+}
+
+// WriteBinary writes g in the binary graph format, see OpenMapped. Edge ids
+// are not kept: a mapped graph numbers edges by their position. coords may
+// be nil, otherwise it holds a position for every vertex.
+func WriteBinary(w io.Writer, g Interface, coords []Coord) error {
+	n := g.VertexCount()
+	if coords != nil && len(coords) != n {
+		return ErrCoordCount
+	}
+	h := &binaryHeader{version: binaryVersion, vertexes: uint64(n)}
+	if coords != nil {
+		h.flags |= flagCoords
+	}
+	var rangeErr error
+	for u := 0; u < n; u++ {
+		g.Neighbors(u, func(e Edge) bool {
+			if e.Cost < math.MinInt32 || e.Cost > math.MaxInt32 || uint64(e.To) > math.MaxUint32 {
+				rangeErr = ErrCostRange
+				return false
+			}
+			if h.edges == 0 || int32(e.Cost) < h.minCost {
+				h.minCost = int32(e.Cost)
+			}
+			if h.edges == 0 || int32(e.Cost) > h.maxCost {
+				h.maxCost = int32(e.Cost)
+			}
+			h.edges++
+			return true
+		})
+		if rangeErr != nil {
+			return rangeErr
+		}
+	}
+
+	// the checksum goes first, so the body is produced twice
+	crc := crc32.New(castagnoli)
+	crc.Write(h.encode())
+	if err := writeBody(crc, g, coords); err != nil {
+		return err
+	}
+	h.checksum = crc.Sum32()
+
+	bw := bufio.NewWriter(w)
+	if _, err := bw.Write(h.encode()); err != nil {
+		return err
+	}
+	if err := writeBody(bw, g, coords); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+func writeBody(w io.Writer, g Interface, coords []Coord) error {
+	n := g.VertexCount()
+	buf := make([]byte, 8)
+	written := 0
+	put := func(b []byte) error {
+		written += len(b)
+		_, err := w.Write(b)
+		return err
+	}
+	put64 := func(v uint64) error {
+		binary.LittleEndian.PutUint64(buf, v)
+		return put(buf[:8])
+	}
+	put32 := func(v uint32) error {
+		binary.LittleEndian.PutUint32(buf, v)
+		return put(buf[:4])
+	}
+
+	var err error
+	offset := uint64(0)
+	for u := 0; u < n && err == nil; u++ {
+		err = put64(offset)
+		g.Neighbors(u, func(e Edge) bool {
+			offset++
+			return true
+		})
+	}
+	if err == nil {
+		err = put64(offset)
+	}
+	for u := 0; u < n && err == nil; u++ {
+		g.Neighbors(u, func(e Edge) bool {
+			err = put32(uint32(e.To))
+			return err == nil
+		})
+	}
+	for u := 0; u < n && err == nil; u++ {
+		g.Neighbors(u, func(e Edge) bool {
+			err = put32(uint32(int32(e.Cost)))
+			return err == nil
+		})
+	}
+	if pad := written % 8; err == nil && pad != 0 {
+		err = put(make([]byte, 8-pad))
+	}
+	for _, c := range coords {
+		if err == nil {
+			err = put64(math.Float64bits(c.X))
+		}
+		if err == nil {
+			err = put64(math.Float64bits(c.Y))
+		}
+	}
+	return err
+}
+
+// MappedGraph is a read-only graph searched directly in a memory-mapped
+// binary graph file, without copying it. Edge ids are edge positions in the
+// file. Close it to release the mapping.
+type MappedGraph struct {
+	data    []byte
+	header  *binaryHeader
+	targets int
+	costs   int
+	coords  int
+	mapped  bool
+}
+
+var _ Interface = (*MappedGraph)(nil)
+
+// OpenMapped maps a file written by WriteBinary into memory and checks its
+// version, size and checksum, and that its edges make a valid graph within
+// the cost bounds of the header.
+func OpenMapped(path string) (*MappedGraph, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if info.Size() < headerSize {
+		return nil, ErrBadFormat
+	}
+	data, mapped, err := mapFile(f, int(info.Size()))
+	if err != nil {
+		return nil, err
+	}
+	g, err := newMappedGraph(data)
+	if err != nil {
+		if mapped {
+			unmapFile(data)
+		}
+		return nil, err
+	}
+	g.mapped = mapped
+	return g, nil
+}
+
+func newMappedGraph(data []byte) (*MappedGraph, error) {
+	h, err := decodeHeader(data)
+	if err != nil {
+		return nil, err
+	}
+	if uint64(len(data)-headerSize) != h.bodySize() {
+		return nil, ErrChecksum
+	}
+	header := make([]byte, headerSize)
+	copy(header, data)
+	binary.LittleEndian.PutUint32(header[40:], 0)
+	crc := crc32.Update(crc32.Checksum(header, castagnoli), castagnoli, data[headerSize:])
+	if crc != h.checksum {
+		return nil, ErrChecksum
+	}
+	g := &MappedGraph{data: data, header: h}
+	g.targets = headerSize + 8*int(h.vertexes+1)
+	g.costs = g.targets + 4*int(h.edges)
+	g.coords = (g.costs + 4*int(h.edges) + 7) &^ 7
+	if err := g.verify(); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// verify checks that the offsets split the edges between the vertexes, and
+// that targets and costs are in range, so searches can trust them
+func (g *MappedGraph) verify() error {
+	// read the offsets unconverted, int may be narrower
+	last := uint64(0)
+	for v := 0; v <= g.VertexCount(); v++ {
+		offset := binary.LittleEndian.Uint64(g.data[headerSize+8*v:])
+		if offset < last || v == 0 && offset != 0 {
+			return ErrBadGraph
+		}
+		last = offset
+	}
+	if last != g.header.edges {
+		return ErrBadGraph
+	}
+	min, max := g.CostBounds()
+	for i := 0; i < g.EdgeCount(); i++ {
+		to := binary.LittleEndian.Uint32(g.data[g.targets+4*i:])
+		cost := int(int32(binary.LittleEndian.Uint32(g.data[g.costs+4*i:])))
+		if uint64(to) >= g.header.vertexes || cost < min || cost > max {
+			return ErrBadGraph
+		}
+	}
+	return nil
+}
+
+// Close releases the mapping, the graph must not be used afterwards.
+func (g *MappedGraph) Close() error {
+	data := g.data
+	g.data = nil
+	if g.mapped {
+		return unmapFile(data)
+	}
+	return nil
+}
+
+func (g *MappedGraph) VertexCount() int {
+	return int(g.header.vertexes)
+}
+
+// EdgeCount returns the number of edges.
+func (g *MappedGraph) EdgeCount() int {
+	return int(g.header.edges)
+}
+
+func (g *MappedGraph) offset(vertex int) int {
+	return int(binary.LittleEndian.Uint64(g.data[headerSize+8*vertex:]))
+}
+
+func (g *MappedGraph) Neighbors(vertex int, fn func(e Edge) bool) {
+	first, last := g.offset(vertex), g.offset(vertex+1)
+	for i := first; i < last; i++ {
+		to := int(binary.LittleEndian.Uint32(g.data[g.targets+4*i:]))
+		cost := int(int32(binary.LittleEndian.Uint32(g.data[g.costs+4*i:])))
+		if !fn(Edge{ID: EdgeID(i), From: vertex, To: to, Cost: cost}) {
+			return
+		}
+	}
+}
+
+func (g *MappedGraph) EdgeCost(from int, to int) (int, bool) {
+	cost, found := 0, false
+	g.Neighbors(from, func(e Edge) bool {
+		if e.To == to && (!found || e.Cost < cost) {
+			cost, found = e.Cost, true
+		}
+		return true
+	})
+	return cost, found
+}
+
+func (g *MappedGraph) CostBounds() (int, int) {
+	return int(g.header.minCost), int(g.header.maxCost)
+}
+
+// Coord returns the position of the vertex, and false when the file has
+// no coordinates.
+func (g *MappedGraph) Coord(vertex int) (Coord, bool) {
+	if g.header.flags&flagCoords == 0 {
+		return Coord{}, false
+	}
+	at := g.coords + 16*vertex
+	return Coord{
+		X: math.Float64frombits(binary.LittleEndian.Uint64(g.data[at:])),
+		Y: math.Float64frombits(binary.LittleEndian.Uint64(g.data[at+8:])),
+	}, true
+}
@@ -0,0 +1,177 @@
+package dijkstra_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	algo "github.com/octo47/gomisc/algo/dijkstra"
+	"github.com/octo47/gomisc/algo/dijkstra/graphgen"
+)
+
+func writeGraphFile(t *testing.T, g algo.Interface, coords []algo.Coord) string {
+	var buf bytes.Buffer
+	if err := algo.WriteBinary(&buf, g, coords); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "graph.bin")
+	if err := ioutil.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestMappedGraph(t *testing.T) {
+	graph, points := graphgen.RandomGeometric(300, 0.1, 3)
+	coords := make([]algo.Coord, len(points))
+	for i, p := range points {
+		coords[i] = algo.Coord{X: p.X, Y: p.Y}
+	}
+
+	mapped, err := algo.OpenMapped(writeGraphFile(t, graph, coords))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mapped.Close()
+
+	if mapped.VertexCount() != 300 {
+		t.Fatal("Wrong vertex count", mapped.VertexCount())
+	}
+	if c, ok := mapped.Coord(42); !ok || c != coords[42] {
+		t.Error("Wrong coordinates", c, ok)
+	}
+	wantMin, wantMax := algo.UndefDist, 0
+	for u := 0; u < 300; u++ {
+		graph.Neighbors(u, func(e algo.Edge) bool {
+			if e.Cost < wantMin {
+				wantMin = e.Cost
+			}
+			if e.Cost > wantMax {
+				wantMax = e.Cost
+			}
+			return true
+		})
+	}
+	if min, max := mapped.CostBounds(); min != wantMin || max != wantMax {
+		t.Error("Wrong cost bounds", min, max)
+	}
+
+	want, _ := graph.Dijkstra(0)
+	got, err := algo.Dijkstra(mapped, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for v := 0; v < 300; v++ {
+		if got.PathCost(v) != want.PathCost(v) {
+			t.Fatal("Mapped graph disagrees", v)
+		}
+		if cost, ok := graph.EdgeCost(0, v); ok {
+			if mappedCost, _ := mapped.EdgeCost(0, v); mappedCost != cost {
+				t.Error("Wrong edge cost", v)
+			}
+		}
+	}
+}
+
+func TestMappedGraphWithoutCoords(t *testing.T) {
+	graph := algo.NewGraph()
+	graph.AddVertexes(3)
+	graph.AddEdge(0, 1, -4, false)
+	graph.AddEdge(1, 2, 7, true)
+
+	mapped, err := algo.OpenMapped(writeGraphFile(t, graph, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mapped.Close()
+	if _, ok := mapped.Coord(0); ok {
+		t.Error("File has no coordinates")
+	}
+	if mapped.EdgeCount() != 3 {
+		t.Error("Wrong edge count", mapped.EdgeCount())
+	}
+	if min, max := mapped.CostBounds(); min != -4 || max != 7 {
+		t.Error("Wrong cost bounds", min, max)
+	}
+	if cost, ok := mapped.EdgeCost(2, 1); !ok || cost != 7 {
+		t.Error("Wrong edge cost", cost, ok)
+	}
+}
+
+// resum recomputes the checksum of a patched binary graph file
+func resum(data []byte) []byte {
+	binary.LittleEndian.PutUint32(data[40:], 0)
+	binary.LittleEndian.PutUint32(data[40:], crc32.Checksum(data, crc32.MakeTable(crc32.Castagnoli)))
+	return data
+}
+
+func TestMappedGraphCorrupted(t *testing.T) {
+	graph := graphgen.Grid(5, 5, 0, 1)
+	var buf bytes.Buffer
+	algo.WriteBinary(&buf, graph, nil)
+	good := buf.Bytes()
+
+	dir := t.TempDir()
+	open := func(data []byte) error {
+		path := filepath.Join(dir, "graph.bin")
+		ioutil.WriteFile(path, data, 0644)
+		g, err := algo.OpenMapped(path)
+		if err == nil {
+			g.Close()
+		}
+		return err
+	}
+
+	flipped := append([]byte{}, good...)
+	flipped[len(flipped)-3] ^= 1
+	if err := open(flipped); err != algo.ErrChecksum {
+		t.Error("Corruption must be detected", err)
+	}
+	if err := open(good[:len(good)-8]); err != algo.ErrChecksum {
+		t.Error("Truncation must be detected", err)
+	}
+	if err := open([]byte("definitely not a graph file at all, not even close")); err != algo.ErrBadFormat {
+		t.Error("Wrong magic must be detected", err)
+	}
+	future := append([]byte{}, good...)
+	future[8] = 3
+	if err := open(future); err != algo.ErrFormatVersion {
+		t.Error("Unknown version must be rejected", err)
+	}
+	// the header is checksummed too
+	bounds := append([]byte{}, good...)
+	bounds[36]++
+	if err := open(bounds); err != algo.ErrChecksum {
+		t.Error("Patched cost bounds must be detected", err)
+	}
+	huge := append([]byte{}, good...)
+	huge[23] = 0x80
+	if err := open(huge); err != algo.ErrBadFormat {
+		t.Error("Huge vertex count must be rejected", err)
+	}
+
+	// a correctly checksummed file may still be broken
+	target := append([]byte{}, good...)
+	// the first edge target follows 26 offsets
+	binary.LittleEndian.PutUint32(target[48+8*26:], 25)
+	if err := open(resum(target)); err != algo.ErrBadGraph {
+		t.Error("Target out of range must be rejected", err)
+	}
+	offsets := append([]byte{}, good...)
+	binary.LittleEndian.PutUint64(offsets[48+8*3:], 1)
+	if err := open(resum(offsets)); err != algo.ErrBadGraph {
+		t.Error("Decreasing offsets must be rejected", err)
+	}
+	costs := append([]byte{}, good...)
+	binary.LittleEndian.PutUint32(costs[36:], 0)
+	if err := open(resum(costs)); err != algo.ErrBadGraph {
+		t.Error("Costs above the bounds must be rejected", err)
+	}
+
+	if err := algo.WriteBinary(&buf, graph, make([]algo.Coord, 3)); err != algo.ErrCoordCount {
+		t.Error("Coordinates must cover every vertex", err)
+	}
+}
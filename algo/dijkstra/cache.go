@@ -0,0 +1,161 @@
+package dijkstra
+
+import (
+	"container/list"
+	"sync"
+)
+
+// Versioner is implemented by graphs which count their changes, see
+// Graph.Version.
+type Versioner interface {
+	Version() uint64
+}
+
+// CacheStats counts how a PathCache is doing.
+type CacheStats struct {
+	Hits   uint64
+	Misses uint64
+	// Evictions counts paths dropped to stay within the memory bound
+	Evictions uint64
+	// Invalidations counts the times the whole cache was dropped because
+	// the graph changed
+	Invalidations uint64
+}
+
+// PathCache keeps the results of Dijkstra for recently asked sources,
+// dropping the least recently used ones to stay within a memory bound.
+// When the graph is a Versioner every change to it drops the whole cache,
+// other graphs are assumed never to change. A PathCache is safe for
+// concurrent use as long as the graph isn't changed concurrently.
+type PathCache struct {
+	mu       sync.Mutex
+	g        Interface
+	opts     []Option
+	maxBytes int
+	bytes    int
+	version  uint64
+	entries  map[int]*list.Element
+	lru      *list.List
+	stats    CacheStats
+}
+
+type cacheEntry struct {
+	source int
+	path   *Path
+	bytes  int
+}
+
+// NewPathCache returns a cache of paths on g holding up to maxBytes of
+// them. The options are passed to every Dijkstra run.
+func NewPathCache(g Interface, maxBytes int, opts ...Option) *PathCache {
+	c := &PathCache{
+		g:        g,
+		opts:     opts,
+		maxBytes: maxBytes,
+		entries:  make(map[int]*list.Element),
+		lru:      list.New(),
+	}
+	c.version = c.graphVersion()
+	return c
+}
+
+// Dijkstra returns the shortest paths from source, from the cache when
+// possible. The returned path is shared and must not be modified.
+func (c *PathCache) Dijkstra(source int) (*Path, error) {
+	c.mu.Lock()
+	c.checkVersion()
+	if el, ok := c.entries[source]; ok {
+		c.lru.MoveToFront(el)
+		c.stats.Hits++
+		path := el.Value.(*cacheEntry).path
+		c.mu.Unlock()
+		return path, nil
+	}
+	c.stats.Misses++
+	version := c.version
+	c.mu.Unlock()
+
+	path, err := Dijkstra(c.g, source, c.opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.checkVersion()
+	if _, ok := c.entries[source]; !ok && c.version == version {
+		c.add(source, path)
+	}
+	return path, nil
+}
+
+// Stats returns the counters of the cache.
+func (c *PathCache) Stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.stats
+}
+
+// Len returns the number of cached paths.
+func (c *PathCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lru.Len()
+}
+
+// Purge drops every cached path.
+func (c *PathCache) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.clear()
+}
+
+func (c *PathCache) graphVersion() uint64 {
+	if v, ok := c.g.(Versioner); ok {
+		return v.Version()
+	}
+	return 0
+}
+
+func (c *PathCache) checkVersion() {
+	if v := c.graphVersion(); v != c.version {
+		c.version = v
+		if c.lru.Len() > 0 {
+			c.stats.Invalidations++
+		}
+		c.clear()
+	}
+}
+
+func (c *PathCache) clear() {
+	c.entries = make(map[int]*list.Element)
+	c.lru.Init()
+	c.bytes = 0
+}
+
+func (c *PathCache) add(source int, path *Path) {
+	size := path.size()
+	if size > c.maxBytes {
+		return
+	}
+	for c.bytes+size > c.maxBytes {
+		el := c.lru.Back()
+		e := el.Value.(*cacheEntry)
+		c.lru.Remove(el)
+		delete(c.entries, e.source)
+		c.bytes -= e.bytes
+		c.stats.Evictions++
+	}
+	c.entries[source] = c.lru.PushFront(&cacheEntry{source: source, path: path, bytes: size})
+	c.bytes += size
+}
+
+// size estimates the memory held by the path in bytes
+func (p *Path) size() int {
+	const word = 8
+	size := 64 + word*(len(p.dist)+len(p.prev)+len(p.order))
+	for _, preds := range p.preds {
+		size += word * (3 + len(preds))
+	}
+	return size
+}
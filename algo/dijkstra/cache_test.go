@@ -0,0 +1,112 @@
+package dijkstra_test
+
+import (
+	"sync"
+	"testing"
+
+	algo "github.com/octo47/gomisc/algo/dijkstra"
+	"github.com/octo47/gomisc/algo/dijkstra/graphgen"
+)
+
+func TestPathCacheHits(t *testing.T) {
+	graph := graphgen.Grid(10, 10, 0, 1)
+	cache := algo.NewPathCache(graph, 1<<20)
+
+	first, err := cache.Dijkstra(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, _ := cache.Dijkstra(0)
+	if first != second {
+		t.Error("Second query must come from the cache")
+	}
+	cache.Dijkstra(5)
+	if stats := cache.Stats(); stats.Hits != 1 || stats.Misses != 2 {
+		t.Error("Wrong stats", stats)
+	}
+	if _, err := cache.Dijkstra(100); err != algo.ErrVertexRange {
+		t.Error("Errors must pass through", err)
+	}
+}
+
+func TestPathCacheEviction(t *testing.T) {
+	graph := graphgen.Grid(10, 10, 0, 1)
+	// room for two paths of 100 vertexes
+	cache := algo.NewPathCache(graph, 4000)
+	cache.Dijkstra(0)
+	cache.Dijkstra(1)
+	cache.Dijkstra(0)
+	cache.Dijkstra(2)
+	if cache.Len() != 2 {
+		t.Fatal("Wrong cache size", cache.Len())
+	}
+	// 1 was the least recently used
+	cache.Dijkstra(0)
+	cache.Dijkstra(1)
+	if stats := cache.Stats(); stats.Hits != 2 || stats.Misses != 4 || stats.Evictions != 2 {
+		t.Error("Wrong stats", stats)
+	}
+
+	tiny := algo.NewPathCache(graph, 10)
+	tiny.Dijkstra(0)
+	if tiny.Len() != 0 {
+		t.Error("Paths above the bound must not be cached")
+	}
+}
+
+func TestPathCacheInvalidation(t *testing.T) {
+	graph := algo.NewGraph()
+	graph.AddVertexes(3)
+	graph.AddEdge(0, 1, 5, false)
+	graph.AddEdge(1, 2, 5, false)
+	cache := algo.NewPathCache(graph, 1<<20)
+
+	check := func(want int) {
+		path, err := cache.Dijkstra(0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cost := path.PathCost(2); cost != want {
+			t.Error("Stale path", cost, want)
+		}
+	}
+	check(10)
+	shortcut := graph.AddEdge(0, 2, 3, false)
+	check(3)
+	graph.SetEdgeCost(shortcut, 7)
+	check(7)
+	graph.DelEdge(0, 2)
+	check(10)
+	v := graph.AddVertex()
+	path, _ := cache.Dijkstra(0)
+	if cost := path.PathCost(v); cost != algo.UndefDist {
+		t.Error("New vertex must be known", cost)
+	}
+	if stats := cache.Stats(); stats.Invalidations != 4 || stats.Hits != 0 {
+		t.Error("Wrong stats", stats)
+	}
+}
+
+func TestPathCacheConcurrent(t *testing.T) {
+	graph := graphgen.Grid(20, 20, 0, 1)
+	cache := algo.NewPathCache(graph, 1<<16)
+	var wg sync.WaitGroup
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				source := (i * (w + 1)) % 400
+				path, err := cache.Dijkstra(source)
+				if err != nil || path.PathCost(source) != 0 {
+					t.Error("Wrong path", source, err)
+					return
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+	if stats := cache.Stats(); stats.Hits+stats.Misses != 1600 {
+		t.Error("Wrong stats", stats)
+	}
+}
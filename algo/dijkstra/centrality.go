@@ -0,0 +1,334 @@
+package dijkstra
+
+import (
+	"context"
+	"math/rand"
+	"runtime"
+	"sync"
+)
+
+// centralityBlock is how many sources a worker searches from in a row.
+// Blocks are summed up in order, so results don't depend on the number of
+// workers.
+const centralityBlock = 16
+
+// CentralityOption tunes a centrality computation.
+type CentralityOption func(*centralityOptions)
+
+type centralityOptions struct {
+	workers  int
+	samples  int
+	seed     int64
+	progress func(searched int)
+}
+
+func newCentralityOptions(opts []CentralityOption) *centralityOptions {
+	o := &centralityOptions{workers: runtime.GOMAXPROCS(0)}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.workers < 1 {
+		o.workers = 1
+	}
+	return o
+}
+
+// WithWorkers sets how many searches run in parallel, GOMAXPROCS by
+// default.
+func WithWorkers(n int) CentralityOption {
+	return func(o *centralityOptions) {
+		o.workers = n
+	}
+}
+
+// WithSampling makes the computation search from samples vertexes picked
+// at random with the seed instead of from every vertex, and scale the
+// result up into an estimate. Graphs with no more vertexes than samples
+// are computed exactly.
+func WithSampling(samples int, seed int64) CentralityOption {
+	return func(o *centralityOptions) {
+		o.samples = samples
+		o.seed = seed
+	}
+}
+
+// WithCentralityProgress makes the computation report the number of
+// sources searched from so far, after every round of parallel searches.
+func WithCentralityProgress(fn func(searched int)) CentralityOption {
+	return func(o *centralityOptions) {
+		o.progress = fn
+	}
+}
+
+// sources returns the vertexes to search from, all of them unless sampling
+func (o *centralityOptions) sources(n int) []int {
+	if o.samples > 0 && o.samples < n {
+		return rand.New(rand.NewSource(o.seed)).Perm(n)[:o.samples]
+	}
+	sources := make([]int, n)
+	for i := range sources {
+		sources[i] = i
+	}
+	return sources
+}
+
+// Betweenness returns the betweenness centrality of the vertexes of g, see
+// the package function.
+func (g *Graph) Betweenness(opts ...CentralityOption) ([]float64, error) {
+	return Betweenness(g, opts...)
+}
+
+// Closeness returns the closeness centrality of the vertexes of g, see the
+// package function.
+func (g *Graph) Closeness(opts ...CentralityOption) ([]float64, error) {
+	return Closeness(g, opts...)
+}
+
+// Harmonic returns the harmonic centrality of the vertexes of g, see the
+// package function.
+func (g *Graph) Harmonic(opts ...CentralityOption) ([]float64, error) {
+	return Harmonic(g, opts...)
+}
+
+// Betweenness returns for every vertex the number of shortest paths
+// between other vertexes running through it, each pair of vertexes
+// weighing one in total. Paths are directed, so an undirected graph counts
+// every pair twice. It runs Brandes' algorithm over the shortest path DAGs
+// recorded by WithPredecessors, so costs must be non-negative. With
+// WithSampling it is the Brandes-Pich estimate.
+func Betweenness(g Interface, opts ...CentralityOption) ([]float64, error) {
+	return BetweennessContext(context.Background(), g, opts...)
+}
+
+// BetweennessContext is Betweenness which gives up with ctx.Err() once the
+// context is done.
+func BetweennessContext(ctx context.Context, g Interface, opts ...CentralityOption) ([]float64, error) {
+	n := g.VertexCount()
+	if min, _ := costBounds(g); min < 0 {
+		return nil, ErrNegativeCost
+	}
+	o := newCentralityOptions(opts)
+	sources := o.sources(n)
+	scale := float64(n) / float64(len(sources))
+
+	return accumulate(ctx, n, sources, o, func(source int, sum []float64) error {
+		path, err := DijkstraContext(ctx, g, source, WithPredecessors())
+		if err != nil {
+			return err
+		}
+		// number of shortest paths from source to every vertex, then the
+		// dependency of source on every vertex, in topological order and
+		// back
+		sigma := make([]float64, n)
+		delta := make([]float64, n)
+		sigma[source] = 1
+		for _, v := range path.order {
+			for _, p := range path.preds[v] {
+				sigma[v] += sigma[p]
+			}
+		}
+		for i := len(path.order) - 1; i > 0; i-- {
+			w := path.order[i]
+			for _, p := range path.preds[w] {
+				delta[p] += sigma[p] / sigma[w] * (1 + delta[w])
+			}
+			sum[w] += delta[w] * scale
+		}
+		return nil
+	})
+}
+
+// Closeness returns for every vertex how close it is to the vertexes it
+// can reach: the number of them over the sum of their distances, scaled
+// by the share of the graph they make up (Wasserman and Faust), so
+// vertexes reaching few others don't look central. With WithSampling
+// distances to the sampled vertexes only are used.
+func Closeness(g Interface, opts ...CentralityOption) ([]float64, error) {
+	return ClosenessContext(context.Background(), g, opts...)
+}
+
+// ClosenessContext is Closeness which gives up with ctx.Err() once the
+// context is done.
+func ClosenessContext(ctx context.Context, g Interface, opts ...CentralityOption) ([]float64, error) {
+	n := g.VertexCount()
+	reached, total, err := distanceSums(ctx, g, opts, func(dist int) float64 {
+		return float64(dist)
+	})
+	if err != nil {
+		return nil, err
+	}
+	closeness := make([]float64, n)
+	for v := range closeness {
+		if total[v] > 0 {
+			closeness[v] = reached[v] / total[v] * reached[v] / float64(n-1)
+		}
+	}
+	return closeness, nil
+}
+
+// Harmonic returns for every vertex the sum of the inverse distances to
+// the other vertexes, unreachable ones adding nothing. Vertexes at
+// distance zero are left out too. With WithSampling the sum over the
+// sampled vertexes is scaled up.
+func Harmonic(g Interface, opts ...CentralityOption) ([]float64, error) {
+	return HarmonicContext(context.Background(), g, opts...)
+}
+
+// HarmonicContext is Harmonic which gives up with ctx.Err() once the
+// context is done.
+func HarmonicContext(ctx context.Context, g Interface, opts ...CentralityOption) ([]float64, error) {
+	_, total, err := distanceSums(ctx, g, opts, func(dist int) float64 {
+		return 1 / float64(dist)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return total, nil
+}
+
+// distanceSums sums fn of the distances from every vertex to the others,
+// and counts how many of them each vertex reaches. Exact sums search from
+// every vertex; sampled ones search backwards from the samples and scale
+// up what they find.
+func distanceSums(ctx context.Context, g Interface, opts []CentralityOption, fn func(dist int) float64) ([]float64, []float64, error) {
+	n := g.VertexCount()
+	if min, _ := costBounds(g); min < 0 {
+		return nil, nil, ErrNegativeCost
+	}
+	o := newCentralityOptions(opts)
+	sources := o.sources(n)
+	// sums of fn go first, the counts after them
+	var both []float64
+	var err error
+	if len(sources) == n {
+		both, err = accumulate(ctx, 2*n, sources, o, func(source int, sum []float64) error {
+			path, err := DijkstraContext(ctx, g, source)
+			if err != nil {
+				return err
+			}
+			for v, dist := range path.dist {
+				if v != source && dist != UndefDist && dist > 0 {
+					sum[source] += fn(dist)
+					sum[n+source]++
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		return both[n:], both[:n], nil
+	}
+
+	reverse := reversed(g)
+	both, err = accumulate(ctx, 2*n, sources, o, func(source int, sum []float64) error {
+		path, err := DijkstraContext(ctx, reverse, source)
+		if err != nil {
+			return err
+		}
+		for v, dist := range path.dist {
+			if v != source && dist != UndefDist && dist > 0 {
+				sum[v] += fn(dist)
+				sum[n+v]++
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	// a sample doesn't see itself, so it saw one sample less than the
+	// others out of the same n-1 vertexes
+	k := float64(len(sources))
+	scale := make([]float64, n)
+	for v := range scale {
+		scale[v] = float64(n-1) / k
+	}
+	for _, v := range sources {
+		scale[v] = 0
+		if k > 1 {
+			scale[v] = float64(n-1) / (k - 1)
+		}
+	}
+	for v := 0; v < n; v++ {
+		both[v] *= scale[v]
+		both[n+v] *= scale[v]
+	}
+	return both[n:], both[:n], nil
+}
+
+// accumulate runs fn for every source, adding into a slice of n sums. The
+// sources are cut into blocks each summed up by one worker, and the block
+// sums are added up in order, a round of blocks at a time, so floating
+// point rounding doesn't depend on the workers.
+func accumulate(ctx context.Context, n int, sources []int, o *centralityOptions, fn func(source int, sum []float64) error) ([]float64, error) {
+	total := make([]float64, n)
+	workers := o.workers
+	sums := make([][]float64, workers)
+	errs := make([]error, workers)
+	for w := range sums {
+		sums[w] = make([]float64, n)
+	}
+	searched := 0
+	for searched < len(sources) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		var wg sync.WaitGroup
+		blocks := 0
+		for w := 0; w < workers; w++ {
+			from := searched + w*centralityBlock
+			if from >= len(sources) {
+				break
+			}
+			to := from + centralityBlock
+			if to > len(sources) {
+				to = len(sources)
+			}
+			blocks++
+			wg.Add(1)
+			go func(w int, block []int) {
+				defer wg.Done()
+				sum := sums[w]
+				for i := range sum {
+					sum[i] = 0
+				}
+				errs[w] = nil
+				for _, source := range block {
+					if errs[w] = fn(source, sum); errs[w] != nil {
+						return
+					}
+				}
+			}(w, sources[from:to])
+		}
+		wg.Wait()
+		for w := 0; w < blocks; w++ {
+			if errs[w] != nil {
+				return nil, errs[w]
+			}
+			for v, x := range sums[w] {
+				total[v] += x
+			}
+		}
+		searched += blocks * centralityBlock
+		if searched > len(sources) {
+			searched = len(sources)
+		}
+		if o.progress != nil {
+			o.progress(searched)
+		}
+	}
+	return total, nil
+}
+
+// reversed copies g with every edge turned around
+func reversed(g Interface) *Graph {
+	r := newGraphOf(g.VertexCount())
+	for u := 0; u < g.VertexCount(); u++ {
+		g.Neighbors(u, func(e Edge) bool {
+			r.AddEdge(e.To, e.From, e.Cost, false)
+			return true
+		})
+	}
+	return r
+}
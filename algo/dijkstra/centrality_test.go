@@ -0,0 +1,253 @@
+package dijkstra_test
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	algo "github.com/octo47/gomisc/algo/dijkstra"
+	"github.com/octo47/gomisc/algo/dijkstra/graphgen"
+)
+
+func closeTo(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func checkCentrality(t *testing.T, name string, got []float64, want []float64) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatal(name, "wrong length", len(got))
+	}
+	for v := range want {
+		if !closeTo(got[v], want[v]) {
+			t.Error(name, "of", v, "is", got[v], "want", want[v])
+		}
+	}
+}
+
+func lineGraph(n int) *algo.Graph {
+	graph := algo.NewGraph()
+	graph.AddVertexes(n)
+	for v := 1; v < n; v++ {
+		graph.AddEdge(v-1, v, 1, true)
+	}
+	return graph
+}
+
+func TestBetweenness(t *testing.T) {
+	bc, err := lineGraph(5).Betweenness()
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkCentrality(t, "line", bc, []float64{0, 6, 8, 6, 0})
+
+	// a cheap detour through 1 instead of the direct edge
+	graph := algo.NewGraph()
+	graph.AddVertexes(3)
+	graph.AddEdge(0, 2, 5, false)
+	graph.AddEdge(0, 1, 1, false)
+	graph.AddEdge(1, 2, 1, false)
+	bc, _ = graph.Betweenness()
+	checkCentrality(t, "detour", bc, []float64{0, 1, 0})
+
+	// two equal ways around a square split every pair
+	square := algo.NewGraph()
+	square.AddVertexes(4)
+	square.AddEdge(0, 1, 1, true)
+	square.AddEdge(1, 3, 1, true)
+	square.AddEdge(0, 2, 1, true)
+	square.AddEdge(2, 3, 1, true)
+	bc, _ = square.Betweenness()
+	checkCentrality(t, "square", bc, []float64{1, 1, 1, 1})
+
+	negative := algo.NewGraph()
+	negative.AddVertexes(2)
+	negative.AddEdge(0, 1, -1, false)
+	if _, err := negative.Betweenness(); err != algo.ErrNegativeCost {
+		t.Error("Negative cost accepted", err)
+	}
+}
+
+// bruteBetweenness counts shortest paths between every pair with
+// Floyd-Warshall
+func bruteBetweenness(g *algo.Graph) []float64 {
+	n := g.VertexCount()
+	dist := make([][]int, n)
+	count := make([][]float64, n)
+	for u := range dist {
+		dist[u] = make([]int, n)
+		count[u] = make([]float64, n)
+		for v := range dist[u] {
+			dist[u][v] = algo.UndefDist
+			if cost, ok := g.EdgeCost(u, v); ok && u != v {
+				dist[u][v], count[u][v] = cost, 1
+			}
+		}
+		dist[u][u], count[u][u] = 0, 1
+	}
+	for k := 0; k < n; k++ {
+		for u := 0; u < n; u++ {
+			for v := 0; v < n; v++ {
+				if k == u || k == v || dist[u][k] == algo.UndefDist || dist[k][v] == algo.UndefDist {
+					continue
+				}
+				if alt := dist[u][k] + dist[k][v]; alt < dist[u][v] {
+					dist[u][v], count[u][v] = alt, count[u][k]*count[k][v]
+				} else if alt == dist[u][v] {
+					count[u][v] += count[u][k] * count[k][v]
+				}
+			}
+		}
+	}
+	bc := make([]float64, n)
+	for s := 0; s < n; s++ {
+		for d := 0; d < n; d++ {
+			if s == d || dist[s][d] == algo.UndefDist {
+				continue
+			}
+			for v := 0; v < n; v++ {
+				if v != s && v != d && dist[s][v] != algo.UndefDist && dist[v][d] != algo.UndefDist &&
+					dist[s][v]+dist[v][d] == dist[s][d] {
+					bc[v] += count[s][v] * count[v][d] / count[s][d]
+				}
+			}
+		}
+	}
+	return bc
+}
+
+func TestBetweennessRandom(t *testing.T) {
+	for seed := int64(1); seed <= 5; seed++ {
+		graph := graphgen.ErdosRenyi(25, 0.15, 3, seed)
+		want := bruteBetweenness(graph)
+		for _, workers := range []int{1, 4} {
+			got, err := graph.Betweenness(algo.WithWorkers(workers))
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkCentrality(t, "random", got, want)
+		}
+	}
+}
+
+func TestBetweennessZeroCost(t *testing.T) {
+	// free edges only run up the ids, so there are no free cycles
+	for seed := int64(1); seed <= 5; seed++ {
+		rng := rand.New(rand.NewSource(seed))
+		graph := algo.NewGraph()
+		graph.AddVertexes(20)
+		for u := 0; u < 20; u++ {
+			for v := 0; v < 20; v++ {
+				if u == v || rng.Float64() > 0.2 {
+					continue
+				}
+				cost := rng.Intn(3)
+				if cost == 0 && u > v {
+					cost = 1
+				}
+				graph.AddEdge(u, v, cost, false)
+			}
+		}
+		got, err := graph.Betweenness()
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkCentrality(t, "zero cost", got, bruteBetweenness(graph))
+	}
+
+	// either order of adding the edges puts 1 on one of the two paths
+	// from 0 to 2 and to 3
+	for _, first := range [][2]int{{1, 2}, {2, 1}} {
+		graph := algo.NewGraph()
+		graph.AddVertexes(4)
+		graph.AddEdge(0, first[0], 1, false)
+		graph.AddEdge(0, first[1], 1, false)
+		graph.AddEdge(1, 2, 0, false)
+		graph.AddEdge(2, 3, 1, false)
+		got, _ := graph.Betweenness()
+		checkCentrality(t, "free edge", got, []float64{0, 1, 2, 0})
+	}
+}
+
+func TestClosenessAndHarmonic(t *testing.T) {
+	graph := lineGraph(3)
+	closeness, err := graph.Closeness()
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkCentrality(t, "closeness", closeness, []float64{2.0 / 3, 1, 2.0 / 3})
+	harmonic, _ := graph.Harmonic()
+	checkCentrality(t, "harmonic", harmonic, []float64{1.5, 2, 1.5})
+
+	// vertex 2 reaches nobody, 0 only reaches 1
+	directed := algo.NewGraph()
+	directed.AddVertexes(3)
+	directed.AddEdge(0, 1, 2, false)
+	directed.AddEdge(1, 2, 2, false)
+	closeness, _ = directed.Closeness()
+	checkCentrality(t, "directed closeness", closeness, []float64{2.0 / 6, 0.5 * 0.5, 0})
+	harmonic, _ = directed.Harmonic()
+	checkCentrality(t, "directed harmonic", harmonic, []float64{0.75, 0.5, 0})
+}
+
+func TestCentralitySampling(t *testing.T) {
+	graph := graphgen.ErdosRenyi(40, 0.2, 5, 3)
+	exact, _ := graph.Betweenness()
+	all, _ := graph.Betweenness(algo.WithSampling(40, 1))
+	checkCentrality(t, "all sampled", all, exact)
+
+	first, _ := graph.Betweenness(algo.WithSampling(10, 7))
+	second, _ := graph.Betweenness(algo.WithSampling(10, 7), algo.WithWorkers(3))
+	checkCentrality(t, "same seed", second, first)
+	for workers := 1; workers <= 4; workers++ {
+		again, _ := graph.Betweenness(algo.WithWorkers(workers))
+		if !reflect.DeepEqual(again, exact) {
+			t.Error("Result depends on the worker count", workers)
+		}
+	}
+
+	// on a complete graph with unit costs every sample is at distance one,
+	// samples included once scaled for the sample they can't see
+	complete := graphgen.Complete(20, 1, 1)
+	harmonic, err := complete.Harmonic(algo.WithSampling(5, 2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	closeness, _ := complete.Closeness(algo.WithSampling(5, 2))
+	for v := range harmonic {
+		if !closeTo(harmonic[v], 19) {
+			t.Error("Wrong harmonic estimate of", v, harmonic[v])
+		}
+		if !closeTo(closeness[v], 1) {
+			t.Error("Wrong closeness estimate of", v, closeness[v])
+		}
+	}
+}
+
+func TestCentralityContext(t *testing.T) {
+	graph := graphgen.ErdosRenyi(40, 0.2, 5, 3)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := algo.BetweennessContext(ctx, graph); err != context.Canceled {
+		t.Error("Betweenness must stop", err)
+	}
+	if _, err := algo.ClosenessContext(ctx, graph); err != context.Canceled {
+		t.Error("Closeness must stop", err)
+	}
+	if _, err := algo.HarmonicContext(ctx, graph, algo.WithSampling(10, 1)); err != context.Canceled {
+		t.Error("Harmonic must stop", err)
+	}
+
+	counts := make([]int, 0)
+	progress := algo.WithCentralityProgress(func(searched int) {
+		counts = append(counts, searched)
+	})
+	if _, err := graph.Harmonic(algo.WithWorkers(1), progress); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(counts, []int{16, 32, 40}) {
+		t.Error("Wrong progress reports", counts)
+	}
+}
@@ -0,0 +1,42 @@
+package dijkstra
+
+import "context"
+
+// checkInterval is how many vertexes a search settles between looking at
+// its context and reporting progress
+const checkInterval = 4096
+
+// monitor watches a running search for cancellation and reports its
+// progress
+type monitor struct {
+	ctx      context.Context
+	progress func(settled int)
+	settled  int
+}
+
+func newMonitor(ctx context.Context, o *options) (*monitor, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return &monitor{ctx: ctx, progress: o.progress}, nil
+}
+
+// settle counts a settled vertex and returns ctx.Err() once the context
+// is done
+func (m *monitor) settle() error {
+	m.settled++
+	if m.settled%checkInterval != 0 {
+		return nil
+	}
+	if m.progress != nil {
+		m.progress(m.settled)
+	}
+	return m.ctx.Err()
+}
+
+// finish reports the final count unless settle just did
+func (m *monitor) finish() {
+	if m.progress != nil && m.settled%checkInterval != 0 {
+		m.progress(m.settled)
+	}
+}
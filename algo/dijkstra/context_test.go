@@ -0,0 +1,127 @@
+package dijkstra_test
+
+import (
+	"context"
+	"testing"
+
+	algo "github.com/octo47/gomisc/algo/dijkstra"
+	"github.com/octo47/gomisc/algo/dijkstra/graphgen"
+)
+
+func TestSearchCancelled(t *testing.T) {
+	grid := graphgen.Grid(100, 100, 0, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := grid.DijkstraContext(ctx, 0); err != context.Canceled {
+		t.Error("Dijkstra must stop", err)
+	}
+	if _, err := algo.BFSContext(ctx, grid, 0); err != context.Canceled {
+		t.Error("BFS must stop", err)
+	}
+	if _, _, err := algo.AStarContext(ctx, grid, 0, 9999, func(int) int { return 0 }); err != context.Canceled {
+		t.Error("A* must stop", err)
+	}
+	if _, err := algo.SearchContext(ctx, grid, 0, algo.Widest); err != context.Canceled {
+		t.Error("Search must stop", err)
+	}
+}
+
+func TestSearchCancelledMidway(t *testing.T) {
+	grid := graphgen.Grid(100, 100, 0, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// cancel after the first progress report
+	reports := 0
+	progress := algo.WithProgress(func(settled int) {
+		reports++
+		cancel()
+	})
+	if _, err := algo.DijkstraContext(ctx, grid, 0, progress); err != context.Canceled {
+		t.Error("Dijkstra must stop", err)
+	}
+	if reports != 1 {
+		t.Error("Search must stop at the first check", reports)
+	}
+}
+
+func TestSearchProgress(t *testing.T) {
+	grid := graphgen.Grid(100, 100, 0, 1)
+	counts := make([]int, 0)
+	progress := algo.WithProgress(func(settled int) {
+		counts = append(counts, settled)
+	})
+	if _, err := algo.BFS(grid, 0, progress); err != nil {
+		t.Fatal(err)
+	}
+	if len(counts) < 2 || counts[len(counts)-1] != 10000 {
+		t.Fatal("Wrong progress reports", counts)
+	}
+	for i := 1; i < len(counts); i++ {
+		if counts[i] <= counts[i-1] {
+			t.Error("Progress must grow", counts)
+		}
+	}
+}
+
+func TestAlgorithmsCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	grid := graphgen.Grid(10, 10, 0, 1)
+	if _, err := algo.MaxFlowContext(ctx, grid, 0, 99); err != context.Canceled {
+		t.Error("MaxFlow must stop", err)
+	}
+
+	flow := algo.NewFlowGraph()
+	flow.AddVertexes(2)
+	flow.AddEdge(0, 1, 1, 1)
+	if _, err := flow.MinCostFlowContext(ctx, 0, 1, 1); err != context.Canceled {
+		t.Error("MinCostFlow must stop", err)
+	}
+	if _, err := flow.MinCostMaxFlowContext(ctx, 0, 1); err != context.Canceled {
+		t.Error("MinCostMaxFlow must stop", err)
+	}
+
+	criteria := algo.NewCriteriaGraph(2)
+	criteria.AddVertexes(2)
+	criteria.AddEdge(0, 1, 1, 1)
+	if _, err := criteria.ParetoPathsContext(ctx, 0, 1); err != context.Canceled {
+		t.Error("ParetoPaths must stop", err)
+	}
+	if _, err := criteria.ConstrainedShortestPathContext(ctx, 0, 1, 0, 1, 5); err != context.Canceled {
+		t.Error("ConstrainedShortestPath must stop", err)
+	}
+
+	timed := algo.NewTimeDependentGraph()
+	timed.AddVertexes(2)
+	timed.AddEdge(0, 1, algo.ConstantTime(1))
+	if _, err := timed.EarliestArrivalContext(ctx, 0, 0); err != context.Canceled {
+		t.Error("EarliestArrival must stop", err)
+	}
+}
+
+func TestAlgorithmsProgress(t *testing.T) {
+	// every middle vertex carries an augmenting path of its own
+	graph := algo.NewGraph()
+	graph.AddVertexes(12)
+	for i := 1; i <= 10; i++ {
+		graph.AddEdge(0, i, 1, false)
+		graph.AddEdge(i, 11, 1, false)
+	}
+	last := 0
+	flow, err := algo.MaxFlowContext(context.Background(), graph, 0, 11, algo.WithProgress(func(paths int) {
+		last = paths
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if flow.Value != 10 || last != 10 {
+		t.Error("Wrong progress", flow.Value, last)
+	}
+
+	if _, err := algo.MaxFlowContext(context.Background(), graph, 0, 11, algo.WithPredecessors()); err != algo.ErrUnsupportedOption {
+		t.Error("Option silently ignored", err)
+	}
+}
@@ -1,40 +1,76 @@
 package dijkstra
 
 import (
+	"context"
+	"errors"
 	"math"
 )
 
 const Undef int = -1
 const UndefDist int = math.MaxInt32
 
+var ErrNoEdge = errors.New("Edge does not exist")
+
+// EdgeID identifies an edge returned by AddEdge. Both directions of an
+// edge added with bidir share the same id.
+type EdgeID int
+
 type edge struct {
+	id     EdgeID
 	target int
 	cost   int
 }
 
+// edgeEnds remembers where the arcs of an edge live, indexed by EdgeID
+type edgeEnds struct {
+	from  int
+	to    int
+	bidir bool
+	live  bool
+}
+
 type Graph struct {
 	edges [][]edge
+	ends  []edgeEnds
+	// bounds of all costs ever set, for picking the search queue
+	minCost int
+	maxCost int
+	version uint64
 }
 
 type Path struct {
 	source int
 	dist   []int
 	prev   []int
+	// recorded by WithPredecessors only, order is topological in the
+	// predecessors
+	preds [][]int
+	order []int
+	// late tells predecessors were added to settled vertexes, so the
+	// settle order isn't topological any more
+	late  bool
+	marks []int
+	stamp int
 }
 
 func NewGraph() *Graph {
 	s := &Graph{
 		edges: make([][]edge, 0),
+		ends:  make([]edgeEnds, 0),
 	}
 	return s
 }
 
 func (p *Path) BuildPath(target int) []int {
+	return buildPath(p.prev, target)
+}
+
+func buildPath(prev []int, target int) []int {
 	stack := NewStack()
 	u := Undef
 	for u = target; u != Undef; {
 		stack.Push(u)
-		u = p.prev[u]
+		u = prev[u]
 	}
 	return stack.AsSlice()
 }
@@ -45,63 +81,407 @@ func (p *Path) PathCost(target int) int {
 
 // add vertex to graph and return its index
 func (g *Graph) AddVertex() int {
+	g.version++
 	g.edges = append(g.edges, make([]edge, 0))
 	return len(g.edges) - 1
 }
 
 func (g *Graph) AddVertexes(count int) {
+	g.version++
 	for i := 0; i < count; i++ {
 		g.edges = append(g.edges, make([]edge, 0))
 	}
 }
 
+// DelEdge removes every edge from one vertex to another, see DelAllEdges.
 func (g *Graph) DelEdge(from int, to int) {
-	for idx, edge := range g.edges[from] {
-		if edge.target == to {
-			a := g.edges[from]
-			g.edges[from] = append(a[:idx], a[idx+1:]...)
+	g.DelAllEdges(from, to)
+}
+
+// DelAllEdges removes every edge going from one vertex to another and
+// returns how many were removed. Edges added with bidir are removed in
+// both directions.
+func (g *Graph) DelAllEdges(from int, to int) int {
+	ids := make([]EdgeID, 0)
+	for _, e := range g.edges[from] {
+		if e.target == to {
+			ids = append(ids, e.id)
+		}
+	}
+	removed := 0
+	for _, id := range ids {
+		// a bidir self-loop lists the same id twice
+		if g.DelEdgeByID(id) == nil {
+			removed++
 		}
 	}
+	return removed
 }
 
-func (g *Graph) AddEdge(vertex1 int, vertex2 int, cost int, bidir bool) {
-	g.edges[vertex1] = append(g.edges[vertex1], edge{target: vertex2, cost: cost})
+// DelUndirectedEdge removes every edge between two vertexes, whatever its
+// direction, and returns how many were removed.
+func (g *Graph) DelUndirectedEdge(vertex1 int, vertex2 int) int {
+	return g.DelAllEdges(vertex1, vertex2) + g.DelAllEdges(vertex2, vertex1)
+}
+
+// DelEdgeByID removes a single edge, in both directions if it was added
+// with bidir.
+func (g *Graph) DelEdgeByID(id EdgeID) error {
+	if id < 0 || int(id) >= len(g.ends) || !g.ends[id].live {
+		return ErrNoEdge
+	}
+	g.version++
+	ends := &g.ends[id]
+	ends.live = false
+	g.removeArcs(ends.from, id)
+	if ends.bidir && ends.to != ends.from {
+		g.removeArcs(ends.to, id)
+	}
+	return nil
+}
+
+// SetEdgeCost changes the cost of an edge, in both directions if it was
+// added with bidir.
+func (g *Graph) SetEdgeCost(id EdgeID, cost int) error {
+	if id < 0 || int(id) >= len(g.ends) || !g.ends[id].live {
+		return ErrNoEdge
+	}
+	g.version++
+	ends := g.ends[id]
+	g.boundCost(cost)
+	for _, vertex := range []int{ends.from, ends.to} {
+		a := g.edges[vertex]
+		for i := range a {
+			if a[i].id == id {
+				a[i].cost = cost
+			}
+		}
+	}
+	return nil
+}
+
+// Version changes whenever the graph does, so results computed on it can
+// tell when they are stale.
+func (g *Graph) Version() uint64 {
+	return g.version
+}
+
+// CostBounds returns bounds of the edge costs. They are not tightened when
+// edges go away.
+func (g *Graph) CostBounds() (int, int) {
+	return g.minCost, g.maxCost
+}
+
+func (g *Graph) boundCost(cost int) {
+	if cost < g.minCost {
+		g.minCost = cost
+	}
+	if cost > g.maxCost {
+		g.maxCost = cost
+	}
+}
+
+func (g *Graph) removeArcs(vertex int, id EdgeID) {
+	a := g.edges[vertex]
+	kept := a[:0]
+	for _, e := range a {
+		if e.id != id {
+			kept = append(kept, e)
+		}
+	}
+	g.edges[vertex] = kept
+}
+
+// AddEdge adds an edge and returns its id. Parallel edges and self-loops
+// are allowed, every call adds a new edge.
+func (g *Graph) AddEdge(vertex1 int, vertex2 int, cost int, bidir bool) EdgeID {
+	g.version++
+	id := EdgeID(len(g.ends))
+	g.ends = append(g.ends, edgeEnds{from: vertex1, to: vertex2, bidir: bidir, live: true})
+	g.boundCost(cost)
+	g.edges[vertex1] = append(g.edges[vertex1], edge{id: id, target: vertex2, cost: cost})
 	if bidir {
-		g.edges[vertex2] = append(g.edges[vertex2], edge{target: vertex1, cost: cost})
+		g.edges[vertex2] = append(g.edges[vertex2], edge{id: id, target: vertex1, cost: cost})
 	}
+	return id
+}
+
+func (g *Graph) Dijkstra(source int, opts ...Option) (*Path, error) {
+	return Dijkstra(g, source, opts...)
+}
+
+func (g *Graph) DijkstraContext(ctx context.Context, source int, opts ...Option) (*Path, error) {
+	return DijkstraContext(ctx, g, source, opts...)
+}
+
+// Dijkstra finds the shortest paths from source to every vertex of g.
+func Dijkstra(g Interface, source int, opts ...Option) (*Path, error) {
+	return DijkstraContext(context.Background(), g, source, opts...)
 }
 
-func (g *Graph) Dijkstra(source int) (*Path, error) {
-	n := len(g.edges)
-	dist := make([]int, n, n)
-	prev := make([]int, n, n)
-	visited := make([]bool, n, n)
-	for i := 0; i < n; i++ {
-		dist[i] = math.MaxInt32
-		prev[i] = Undef
+// DijkstraContext is Dijkstra which gives up with ctx.Err() once the
+// context is done.
+func DijkstraContext(ctx context.Context, g Interface, source int, opts ...Option) (*Path, error) {
+	n := g.VertexCount()
+	if source < 0 || source >= n {
+		return nil, ErrVertexRange
+	}
+	o := newOptions(opts)
+	g, err := o.view(g)
+	if err != nil {
+		return nil, err
+	}
+	m, err := newMonitor(ctx, o)
+	if err != nil {
+		return nil, err
+	}
+	s := newSearch(n, source, 0, intCosts{}, o, func(dist int) int { return dist })
+	for i := range s.weight {
+		if i != source {
+			s.weight[i] = UndefDist
+		}
+	}
+	q := newVertexQueue(o.queue, g, s.weight, s.settled)
+	s.weighing.negative = q.negativeCosts()
+	s.weighing.maxCost = q.maxCost()
+	if err := s.run(m, g, source, q); err != nil {
+		return nil, err
 	}
-	dist[source] = 0
+	return &Path{source: source, dist: s.weight, prev: s.prev, preds: s.preds, order: s.order}, nil
+}
 
-	for i := 0; i < n; i++ {
-		u := Undef
-		for j := 0; j < n; j++ {
-			if visited[j] {
-				continue
+// intCosts is the arithmetic of Dijkstra: paths add up their int costs
+type intCosts struct {
+	// whether the queue takes negative costs
+	negative bool
+	// Dial's buckets can't take costs above the bound they were sized by
+	maxCost int
+}
+
+func (c intCosts) relax(dist int, e Edge, old int, reached bool) (int, bool, error) {
+	if e.Cost < 0 && !c.negative {
+		return 0, false, ErrNegativeCost
+	}
+	if e.Cost > c.maxCost {
+		return 0, false, ErrCostBounds
+	}
+	alt := dist + e.Cost
+	return alt, !reached || alt < old, nil
+}
+
+// weighing is the arithmetic a search runs on: the int costs of Dijkstra
+// or the weights of an Algebra
+type weighing[W comparable] interface {
+	// relax returns the weight of a path extended by an edge and whether
+	// it is better than the old weight of the edge target, if reached, or
+	// an error if the edge can't be taken. It is one call instead of two
+	// since the search can't inline it.
+	relax(weight W, e Edge, old W, reached bool) (W, bool, error)
+}
+
+// searchQueue hands out vertexes best weight first, see vertexQueue
+type searchQueue[W any] interface {
+	push(vertex int, weight W)
+	// pop returns Undef once nothing reachable is left
+	pop() int
+}
+
+// search is the label-setting loop Dijkstra and Search share
+type search[W comparable, A weighing[W]] struct {
+	weighing A
+	weight   []W
+	reached  []bool
+	settled  []bool
+	prev     []int
+	// recorded by WithPredecessors only, order is topological in the
+	// predecessors
+	preds [][]int
+	order []int
+	// late tells predecessors were added to settled vertexes, so the
+	// settle order isn't topological any more
+	late  bool
+	marks []int
+	stamp int
+	// settle tells the visitor a vertex is settled, by default calling
+	// OnSettle with the weight as an int
+	visitor Visitor
+	settle  func(vertex int, weight W) Action
+}
+
+func newSearch[W comparable, A weighing[W]](n int, source int, identity W, a A, o *options, dist func(weight W) int) *search[W, A] {
+	s := &search[W, A]{
+		weighing: a,
+		weight:   make([]W, n),
+		reached:  make([]bool, n),
+		settled:  make([]bool, n),
+		prev:     make([]int, n),
+		visitor:  o.visitor,
+	}
+	if v := o.visitor; v != nil {
+		s.settle = func(vertex int, weight W) Action {
+			return v.OnSettle(vertex, dist(weight))
+		}
+	}
+	for i := range s.prev {
+		s.prev[i] = Undef
+	}
+	s.weight[source] = identity
+	s.reached[source] = true
+	if o.predecessors {
+		s.preds = make([][]int, n)
+		s.order = make([]int, 0, n)
+	}
+	return s
+}
+
+func (s *search[W, A]) run(m *monitor, g Interface, source int, q searchQueue[W]) error {
+	weight, reached, settled, prev := s.weight, s.reached, s.settled, s.prev
+	visitor := s.visitor
+	if visitor != nil {
+		visitor.OnDiscover(source)
+	}
+	q.push(source, weight[source])
+	stopped := false
+	var failed error
+	u := Undef
+	relax := func(e Edge) bool {
+		v := e.To
+		alt, better, err := s.weighing.relax(weight[u], e, weight[v], reached[v])
+		if err != nil {
+			failed = err
+			return false
+		}
+		if visitor != nil {
+			switch visitor.OnRelax(e) {
+			case Skip:
+				return true
+			case Stop:
+				stopped = true
+				return false
+			}
+		}
+		if better {
+			if visitor != nil && !reached[v] {
+				visitor.OnDiscover(v)
+			}
+			weight[v] = alt
+			reached[v] = true
+			prev[v] = u
+			if s.preds != nil {
+				s.preds[v] = append(s.preds[v][:0], u)
+			}
+			if !settled[v] {
+				q.push(v, alt)
 			}
-			if u == Undef || dist[j] < dist[u] {
-				u = j
+		} else if s.preds != nil && alt == weight[v] {
+			// parallel edges give the same predecessor in a row, and the
+			// source has none
+			if p := s.preds[v]; len(p) > 0 && p[len(p)-1] != u {
+				if !settled[v] {
+					s.preds[v] = append(p, u)
+				} else if !s.precedes(v, u) {
+					// reached by a zero-cost edge after it was settled
+					s.preds[v] = append(p, u)
+					s.late = true
+				}
 			}
 		}
-		visited[u] = true
-		for ni := 0; ni < len(g.edges[u]); ni++ {
-			e := g.edges[u][ni]
-			v := e.target
-			alt := dist[u] + e.cost
-			if alt < dist[v] {
-				dist[v] = alt
-				prev[v] = u
+		return true
+	}
+	for u = q.pop(); u != Undef; u = q.pop() {
+		if settled[u] {
+			continue
+		}
+		settled[u] = true
+		if s.order != nil {
+			s.order = append(s.order, u)
+		}
+		action := Continue
+		if visitor != nil {
+			action = s.settle(u, weight[u])
+		}
+		if action == Stop {
+			break
+		}
+		if action != Skip {
+			g.Neighbors(u, relax)
+		}
+		if failed != nil {
+			return failed
+		}
+		if stopped {
+			break
+		}
+		if err := m.settle(); err != nil {
+			return err
+		}
+	}
+	if s.late {
+		s.order = topological(s.order, s.preds)
+	}
+	m.finish()
+	return nil
+}
+
+// precedes reports whether u lies on a recorded shortest path to v, so
+// making v a predecessor of u would close a cycle of zero-cost edges. Only
+// vertexes as far as u can be on the way.
+func (s *search[W, A]) precedes(u int, v int) bool {
+	if u == v {
+		return true
+	}
+	if s.marks == nil {
+		s.marks = make([]int, len(s.weight))
+	}
+	s.stamp++
+	stack := []int{v}
+	s.marks[v] = s.stamp
+	for len(stack) > 0 {
+		w := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, p := range s.preds[w] {
+			if p == u {
+				return true
+			}
+			if s.marks[p] != s.stamp && s.weight[p] == s.weight[u] {
+				s.marks[p] = s.stamp
+				stack = append(stack, p)
+			}
+		}
+	}
+	return false
+}
+
+// topological orders the vertexes so that predecessors come before the
+// vertexes they precede, keeping the given order otherwise
+func topological(order []int, preds [][]int) []int {
+	sorted := make([]int, 0, len(order))
+	done := make([]bool, len(preds))
+	type frame struct {
+		vertex int
+		next   int
+	}
+	stack := make([]frame, 0)
+	for _, root := range order {
+		if done[root] {
+			continue
+		}
+		done[root] = true
+		stack = append(stack, frame{vertex: root})
+		for len(stack) > 0 {
+			top := &stack[len(stack)-1]
+			if top.next < len(preds[top.vertex]) {
+				p := preds[top.vertex][top.next]
+				top.next++
+				if !done[p] {
+					done[p] = true
+					stack = append(stack, frame{vertex: p})
+				}
+				continue
 			}
+			sorted = append(sorted, top.vertex)
+			stack = stack[:len(stack)-1]
 		}
 	}
-	return &Path{source: source, dist: dist, prev: prev}, nil
+	return sorted
 }
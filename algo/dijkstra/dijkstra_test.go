@@ -43,3 +43,101 @@ func TestSimplePath(t *testing.T) {
 		}
 	}
 }
+
+func TestParallelEdges(t *testing.T) {
+	graph := algo.NewGraph()
+	graph.AddVertexes(3)
+	graph.AddEdge(0, 1, 5, false)
+	graph.AddEdge(0, 1, 4, false)
+	cheap := graph.AddEdge(0, 1, 3, false)
+	graph.AddEdge(0, 2, 9, false)
+	graph.AddEdge(1, 2, 1, false)
+
+	if err := graph.DelEdgeByID(cheap); err != nil {
+		t.Fatal(err)
+	}
+	if err := graph.DelEdgeByID(cheap); err != algo.ErrNoEdge {
+		t.Error("Edge must be removed once", err)
+	}
+	path, _ := graph.Dijkstra(0)
+	if cost := path.PathCost(2); cost != 5 {
+		t.Error("Wrong cost after deleting by id", cost)
+	}
+
+	// every parallel edge goes away, not every other one
+	if removed := graph.DelAllEdges(0, 1); removed != 2 {
+		t.Error("Wrong number of removed edges", removed)
+	}
+	path, _ = graph.Dijkstra(0)
+	if pathTo2 := path.BuildPath(2); !reflect.DeepEqual(pathTo2, []int{2, 0}) {
+		t.Error("Parallel edges left behind", pathTo2)
+	}
+}
+
+func TestSetEdgeCost(t *testing.T) {
+	graph := algo.NewGraph()
+	graph.AddVertexes(3)
+	road := graph.AddEdge(0, 1, 1, true)
+	graph.AddEdge(1, 2, 1, false)
+	graph.AddEdge(0, 2, 5, false)
+
+	if err := graph.SetEdgeCost(road, 10); err != nil {
+		t.Fatal(err)
+	}
+	path, _ := graph.Dijkstra(0)
+	if cost := path.PathCost(2); cost != 5 {
+		t.Error("Cost was not updated", cost)
+	}
+	path, _ = graph.Dijkstra(1)
+	if cost := path.PathCost(0); cost != 10 {
+		t.Error("Reverse direction was not updated", cost)
+	}
+	if err := graph.SetEdgeCost(algo.EdgeID(42), 1); err != algo.ErrNoEdge {
+		t.Error("Expected missing edge error", err)
+	}
+}
+
+func TestDelBidirEdge(t *testing.T) {
+	graph := algo.NewGraph()
+	graph.AddVertexes(2)
+	graph.AddEdge(0, 1, 1, true)
+	graph.AddEdge(1, 0, 2, false)
+
+	// the reverse direction of a bidir edge goes away with it
+	graph.DelEdge(0, 1)
+	path, _ := graph.Dijkstra(1)
+	if cost := path.PathCost(0); cost != 2 {
+		t.Error("Reverse edge left behind", cost)
+	}
+
+	graph.AddEdge(0, 1, 1, false)
+	if removed := graph.DelUndirectedEdge(0, 1); removed != 2 {
+		t.Error("Wrong number of removed edges", removed)
+	}
+	path, _ = graph.Dijkstra(0)
+	if cost := path.PathCost(1); cost != algo.UndefDist {
+		t.Error("Edges left behind", cost)
+	}
+}
+
+func TestSelfLoops(t *testing.T) {
+	graph := algo.NewGraph()
+	graph.AddVertexes(2)
+	loop := graph.AddEdge(0, 0, 1, true)
+	graph.AddEdge(0, 0, 2, false)
+	graph.AddEdge(0, 1, 3, false)
+
+	path, _ := graph.Dijkstra(0)
+	if cost := path.PathCost(1); cost != 3 {
+		t.Error("Self-loops changed the path", cost)
+	}
+	if err := graph.SetEdgeCost(loop, 7); err != nil {
+		t.Error(err)
+	}
+	if removed := graph.DelAllEdges(0, 0); removed != 2 {
+		t.Error("Wrong number of removed loops", removed)
+	}
+	if flow, _ := graph.MaxFlow(0, 1); len(flow.Edges) != 1 {
+		t.Error("Self-loops left behind", flow.Edges)
+	}
+}
@@ -0,0 +1,137 @@
+package dijkstra
+
+import "math/big"
+
+// Predecessors returns every vertex preceding target on a shortest path.
+// Without WithPredecessors only the one kept by BuildPath is known.
+func (p *Path) Predecessors(target int) []int {
+	if p.preds != nil {
+		cp := make([]int, len(p.preds[target]))
+		copy(cp, p.preds[target])
+		return cp
+	}
+	if p.prev[target] == Undef {
+		return []int{}
+	}
+	return []int{p.prev[target]}
+}
+
+func (p *Path) predecessors(target int) []int {
+	if p.preds != nil {
+		return p.preds[target]
+	}
+	if p.prev[target] == Undef {
+		return nil
+	}
+	return p.prev[target : target+1]
+}
+
+// CountPaths returns the number of distinct shortest paths from source to
+// target, zero for unreachable targets. Paths are told apart by their
+// vertexes, so parallel edges of equal cost don't add up. The count grows
+// exponentially on grid-like graphs, hence the big integer.
+func (p *Path) CountPaths(target int) *big.Int {
+	if p.dist[target] == UndefDist {
+		return big.NewInt(0)
+	}
+	if p.preds == nil {
+		return big.NewInt(1)
+	}
+	// the order is topological in the predecessors, so one pass sees every
+	// count complete
+	counts := make(map[int]*big.Int)
+	counts[p.source] = big.NewInt(1)
+	for _, v := range p.order {
+		if v == p.source {
+			continue
+		}
+		sum := big.NewInt(0)
+		for _, u := range p.preds[v] {
+			sum.Add(sum, counts[u])
+		}
+		counts[v] = sum
+		if v == target {
+			break
+		}
+	}
+	return counts[target]
+}
+
+// AllPaths lazily enumerates every shortest path from source to target.
+// Record predecessors with WithPredecessors, otherwise the only path
+// produced is the one of BuildPath.
+func (p *Path) AllPaths(target int) *PathIterator {
+	return &PathIterator{path: p, target: target}
+}
+
+// PathIterator walks the shortest paths to a target one at a time:
+//
+//	for it := path.AllPaths(target); it.Next(); {
+//		use(it.Path())
+//	}
+type PathIterator struct {
+	path    *Path
+	target  int
+	stack   []pathFrame
+	started bool
+	done    bool
+}
+
+// pathFrame is a vertex of the current path and the index of the
+// predecessor chosen for it
+type pathFrame struct {
+	vertex int
+	next   int
+}
+
+// Next advances to the next path and reports whether there is one.
+func (it *PathIterator) Next() bool {
+	if it.done {
+		return false
+	}
+	if !it.started {
+		it.started = true
+		if it.path.dist[it.target] == UndefDist {
+			it.done = true
+			return false
+		}
+		it.stack = append(it.stack, pathFrame{vertex: it.target})
+		it.descend()
+		return true
+	}
+	// backtrack to the deepest vertex with an untried predecessor
+	it.stack = it.stack[:len(it.stack)-1]
+	for len(it.stack) > 0 {
+		top := &it.stack[len(it.stack)-1]
+		top.next++
+		if top.next < len(it.path.predecessors(top.vertex)) {
+			it.descend()
+			return true
+		}
+		it.stack = it.stack[:len(it.stack)-1]
+	}
+	it.done = true
+	return false
+}
+
+// descend follows the chosen predecessors down to the source
+func (it *PathIterator) descend() {
+	for {
+		top := it.stack[len(it.stack)-1]
+		if top.vertex == it.path.source {
+			return
+		}
+		u := it.path.predecessors(top.vertex)[top.next]
+		it.stack = append(it.stack, pathFrame{vertex: u})
+	}
+}
+
+// Path returns the current path, from target back to source like
+// BuildPath does.
+func (it *PathIterator) Path() []int {
+	path := make([]int, len(it.stack))
+	for i, f := range it.stack {
+		path[i] = f.vertex
+	}
+	return path
+}
@@ -0,0 +1,156 @@
+package dijkstra_test
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	algo "github.com/octo47/gomisc/algo/dijkstra"
+	"github.com/octo47/gomisc/algo/dijkstra/graphgen"
+)
+
+func collectPaths(path *algo.Path, target int) [][]int {
+	paths := make([][]int, 0)
+	for it := path.AllPaths(target); it.Next(); {
+		paths = append(paths, it.Path())
+	}
+	return paths
+}
+
+func TestEqualCostPaths(t *testing.T) {
+	// two equal routes 0-1-3 and 0-2-3, a longer 0-3 and a parallel 1-3
+	graph := algo.NewDirectedGraph()
+	graph.AddVertexes(5)
+	graph.AddEdge(0, 1, 1)
+	graph.AddEdge(0, 2, 1)
+	graph.AddEdge(1, 3, 1)
+	graph.AddEdge(1, 3, 1)
+	graph.AddEdge(2, 3, 1)
+	graph.AddEdge(0, 3, 3)
+	graph.AddEdge(3, 4, 1)
+
+	path, err := algo.Dijkstra(graph, 0, algo.WithPredecessors())
+	if err != nil {
+		t.Fatal(err)
+	}
+	preds := path.Predecessors(3)
+	sort.Ints(preds)
+	if !reflect.DeepEqual(preds, []int{1, 2}) {
+		t.Error("Wrong predecessors", preds)
+	}
+	if count := path.CountPaths(4); count.Int64() != 2 {
+		t.Error("Wrong path count", count)
+	}
+	paths := collectPaths(path, 4)
+	sort.Slice(paths, func(i, j int) bool { return paths[i][2] < paths[j][2] })
+	if !reflect.DeepEqual(paths, [][]int{{4, 3, 1, 0}, {4, 3, 2, 0}}) {
+		t.Error("Wrong paths", paths)
+	}
+	if paths := collectPaths(path, 0); !reflect.DeepEqual(paths, [][]int{{0}}) {
+		t.Error("Source must have the empty path", paths)
+	}
+
+	// without predecessors only BuildPath's path is known
+	path, _ = algo.Dijkstra(graph, 0)
+	if count := path.CountPaths(4); count.Int64() != 1 {
+		t.Error("Wrong path count", count)
+	}
+	if paths := collectPaths(path, 4); len(paths) != 1 || !reflect.DeepEqual(paths[0], path.BuildPath(4)) {
+		t.Error("Wrong paths", paths)
+	}
+}
+
+func TestEqualCostPathsGrid(t *testing.T) {
+	// monotone lattice paths across a 5x4 grid: C(7, 3)
+	grid := graphgen.Grid(5, 4, 0, 1)
+	path, err := grid.Dijkstra(0, algo.WithPredecessors())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count := path.CountPaths(19); count.Int64() != 35 {
+		t.Error("Wrong path count", count)
+	}
+	paths := collectPaths(path, 19)
+	if len(paths) != 35 {
+		t.Fatal("Wrong number of paths", len(paths))
+	}
+	seen := make([]string, 0)
+	for _, p := range paths {
+		if len(p) != 8 {
+			t.Error("Path is not shortest", p)
+		}
+		seen = append(seen, sortKey(p))
+	}
+	sort.Strings(seen)
+	for i := 1; i < len(seen); i++ {
+		if seen[i] == seen[i-1] {
+			t.Error("Path enumerated twice", seen[i])
+		}
+	}
+}
+
+func TestEqualCostUnreachable(t *testing.T) {
+	graph := algo.NewDirectedGraph()
+	graph.AddVertexes(2)
+	path, _ := algo.Dijkstra(graph, 0, algo.WithPredecessors())
+	if count := path.CountPaths(1); count.Sign() != 0 {
+		t.Error("Unreachable target has no paths", count)
+	}
+	if paths := collectPaths(path, 1); len(paths) != 0 {
+		t.Error("Unreachable target has no paths", paths)
+	}
+}
+
+func sortKey(path []int) string {
+	b := make([]byte, len(path))
+	for i, v := range path {
+		b[i] = byte('a' + v)
+	}
+	return string(b)
+}
+
+func TestEqualCostPathsZeroCost(t *testing.T) {
+	// 2 is reached from 0 directly and through 1 over a free edge, in
+	// either order of adding the edges
+	for _, first := range [][2]int{{1, 2}, {2, 1}} {
+		graph := algo.NewDirectedGraph()
+		graph.AddVertexes(4)
+		graph.AddEdge(0, first[0], 1)
+		graph.AddEdge(0, first[1], 1)
+		graph.AddEdge(1, 2, 0)
+		graph.AddEdge(2, 3, 1)
+
+		path, err := algo.Dijkstra(graph, 0, algo.WithPredecessors())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if count := path.CountPaths(3); count.Int64() != 2 {
+			t.Error("Wrong path count", first, count)
+		}
+		paths := collectPaths(path, 3)
+		sort.Slice(paths, func(i, j int) bool { return len(paths[i]) < len(paths[j]) })
+		if !reflect.DeepEqual(paths, [][]int{{3, 2, 0}, {3, 2, 1, 0}}) {
+			t.Error("Wrong paths", first, paths)
+		}
+	}
+}
+
+func TestEqualCostPathsZeroCycle(t *testing.T) {
+	// 1 and 2 reach each other for free, the cycle is cut once
+	graph := algo.NewDirectedGraph()
+	graph.AddVertexes(3)
+	graph.AddEdge(0, 1, 1)
+	graph.AddEdge(1, 2, 0)
+	graph.AddEdge(2, 1, 0)
+
+	path, err := algo.Dijkstra(graph, 0, algo.WithPredecessors())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count := path.CountPaths(2); count.Int64() != 1 {
+		t.Error("Wrong path count", count)
+	}
+	if paths := collectPaths(path, 2); !reflect.DeepEqual(paths, [][]int{{2, 1, 0}}) {
+		t.Error("Wrong paths", paths)
+	}
+}
@@ -0,0 +1,187 @@
+package dijkstra
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// MaxReadVertexes is the most vertexes the readers allocate for, so a bad
+// count or id in the input can't exhaust memory.
+const MaxReadVertexes = 1 << 26
+
+var (
+	ErrNoProblemLine   = errors.New("DIMACS graph has no problem line")
+	ErrTooManyVertexes = errors.New("Graph has more vertexes than MaxReadVertexes")
+)
+
+// ReadDIMACS reads a directed graph in the DIMACS shortest path format:
+// comment lines start with "c", the problem line "p sp <vertexes> <edges>"
+// comes first, and every "a <from> <to> <cost>" line is an edge. Vertexes
+// are numbered from 1 in the file and from 0 in the graph.
+func ReadDIMACS(r io.Reader) (*Graph, error) {
+	g := NewGraph()
+	scanner := bufio.NewScanner(r)
+	line := 0
+	problem := false
+	for scanner.Scan() {
+		line++
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || fields[0] == "c" {
+			continue
+		}
+		switch fields[0] {
+		case "p":
+			if problem || len(fields) != 4 || fields[1] != "sp" {
+				return nil, fmt.Errorf("line %d: bad problem line", line)
+			}
+			n, err := strconv.Atoi(fields[2])
+			if err != nil || n < 0 {
+				return nil, fmt.Errorf("line %d: bad vertex count %q", line, fields[2])
+			}
+			if n > MaxReadVertexes {
+				return nil, fmt.Errorf("line %d: %v", line, ErrTooManyVertexes)
+			}
+			g.AddVertexes(n)
+			problem = true
+		case "a":
+			if !problem {
+				return nil, fmt.Errorf("line %d: edge before problem line", line)
+			}
+			if len(fields) != 4 {
+				return nil, fmt.Errorf("line %d: edge needs source, target and cost", line)
+			}
+			edge, err := parseEdge(fields[1:], 1, g.VertexCount())
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %v", line, err)
+			}
+			g.AddEdge(edge[0], edge[1], edge[2], false)
+		default:
+			return nil, fmt.Errorf("line %d: unknown line type %q", line, fields[0])
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if !problem {
+		return nil, ErrNoProblemLine
+	}
+	return g, nil
+}
+
+// ReadCSV reads a graph from "from,to,cost[,bidir]" records. The graph has
+// as many vertexes as the largest id mentioned plus one, ids must be below
+// MaxReadVertexes. A first record which doesn't start with a number is
+// taken for a header.
+func ReadCSV(r io.Reader) (*Graph, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) > 0 && len(records[0]) > 0 {
+		if _, err := strconv.Atoi(records[0][0]); err != nil {
+			records = records[1:]
+		}
+	}
+
+	type csvEdge struct {
+		from, to, cost int
+		bidir          bool
+	}
+	edges := make([]csvEdge, 0, len(records))
+	n := 0
+	for i, rec := range records {
+		if len(rec) != 3 && len(rec) != 4 {
+			return nil, fmt.Errorf("record %d: want from,to,cost[,bidir]", i+1)
+		}
+		edge, err := parseEdge(rec[:3], 0, MaxReadVertexes)
+		if err != nil {
+			return nil, fmt.Errorf("record %d: %v", i+1, err)
+		}
+		e := csvEdge{from: edge[0], to: edge[1], cost: edge[2]}
+		if len(rec) == 4 {
+			if e.bidir, err = strconv.ParseBool(rec[3]); err != nil {
+				return nil, fmt.Errorf("record %d: bad bidir %q", i+1, rec[3])
+			}
+		}
+		if e.from >= n {
+			n = e.from + 1
+		}
+		if e.to >= n {
+			n = e.to + 1
+		}
+		edges = append(edges, e)
+	}
+
+	g := NewGraph()
+	g.AddVertexes(n)
+	for _, e := range edges {
+		g.AddEdge(e.from, e.to, e.cost, e.bidir)
+	}
+	return g, nil
+}
+
+// JSONGraph is the JSON form of a graph read by ReadJSON.
+type JSONGraph struct {
+	Vertexes int        `json:"vertexes"`
+	Edges    []JSONEdge `json:"edges"`
+}
+
+// JSONEdge is an edge of a JSONGraph.
+type JSONEdge struct {
+	From  int  `json:"from"`
+	To    int  `json:"to"`
+	Cost  int  `json:"cost"`
+	Bidir bool `json:"bidir,omitempty"`
+}
+
+// ReadJSON reads a graph in the form of JSONGraph.
+func ReadJSON(r io.Reader) (*Graph, error) {
+	var doc JSONGraph
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, err
+	}
+	if doc.Vertexes < 0 {
+		return nil, fmt.Errorf("bad vertex count %d", doc.Vertexes)
+	}
+	if doc.Vertexes > MaxReadVertexes {
+		return nil, ErrTooManyVertexes
+	}
+	g := NewGraph()
+	g.AddVertexes(doc.Vertexes)
+	for i, e := range doc.Edges {
+		if e.From < 0 || e.From >= doc.Vertexes || e.To < 0 || e.To >= doc.Vertexes {
+			return nil, fmt.Errorf("edge %d: %v", i, ErrVertexRange)
+		}
+		g.AddEdge(e.From, e.To, e.Cost, e.Bidir)
+	}
+	return g, nil
+}
+
+// parseEdge parses from, to and cost, shifting vertexes down by base and
+// checking them against n
+func parseEdge(fields []string, base int, n int) ([3]int, error) {
+	var edge [3]int
+	for i, name := range []string{"source", "target", "cost"} {
+		v, err := strconv.Atoi(strings.TrimSpace(fields[i]))
+		if err != nil {
+			return edge, fmt.Errorf("bad %s %q", name, fields[i])
+		}
+		if i < 2 {
+			v -= base
+			if v < 0 || v >= n {
+				return edge, fmt.Errorf("%s %q: %v", name, fields[i], ErrVertexRange)
+			}
+		}
+		edge[i] = v
+	}
+	return edge, nil
+}
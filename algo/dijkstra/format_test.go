@@ -0,0 +1,91 @@
+package dijkstra_test
+
+import (
+	"strings"
+	"testing"
+
+	algo "github.com/octo47/gomisc/algo/dijkstra"
+)
+
+func TestReadDIMACS(t *testing.T) {
+	graph, err := algo.ReadDIMACS(strings.NewReader(`c sample
+p sp 3 3
+a 1 2 4
+a 2 3 1
+c mid-file comment
+a 1 3 9
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	path, _ := graph.Dijkstra(0)
+	if cost := path.PathCost(2); cost != 5 {
+		t.Error("Wrong path cost", cost)
+	}
+
+	for _, bad := range []string{
+		"a 1 2 3\n",
+		"p sp 2 1\na 1 3 1\n",
+		"p sp 2 1\na 1 2 x\n",
+		"p sp 2 1\nx 1 2 3\n",
+		"c empty\n",
+	} {
+		if _, err := algo.ReadDIMACS(strings.NewReader(bad)); err == nil {
+			t.Error("Bad input accepted", bad)
+		}
+	}
+	if _, err := algo.ReadDIMACS(strings.NewReader("c empty\n")); err != algo.ErrNoProblemLine {
+		t.Error("Missing problem line must be reported", err)
+	}
+	if _, err := algo.ReadDIMACS(strings.NewReader("p sp 1000000000000 0\n")); err == nil {
+		t.Error("Huge vertex count accepted")
+	}
+}
+
+func TestReadCSV(t *testing.T) {
+	graph, err := algo.ReadCSV(strings.NewReader(`from,to,cost,bidir
+0,1,2,true
+1, 2, 3
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if graph.VertexCount() != 3 {
+		t.Fatal("Wrong vertex count", graph.VertexCount())
+	}
+	path, _ := graph.Dijkstra(1)
+	if cost := path.PathCost(0); cost != 2 {
+		t.Error("Bidir edge must be walked back", cost)
+	}
+
+	if _, err := algo.ReadCSV(strings.NewReader("0,1\n")); err == nil {
+		t.Error("Short record accepted")
+	}
+	if _, err := algo.ReadCSV(strings.NewReader("0,-1,1\n")); err == nil {
+		t.Error("Negative vertex accepted")
+	}
+	if _, err := algo.ReadCSV(strings.NewReader("0,1000000000000,1\n")); err == nil {
+		t.Error("Huge vertex accepted")
+	}
+}
+
+func TestReadJSON(t *testing.T) {
+	graph, err := algo.ReadJSON(strings.NewReader(`{"vertexes": 3, "edges": [
+		{"from": 0, "to": 1, "cost": 1},
+		{"from": 1, "to": 2, "cost": 1, "bidir": true}
+	]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	path, _ := graph.Dijkstra(2)
+	if cost := path.PathCost(1); cost != 1 {
+		t.Error("Wrong path cost", cost)
+	}
+
+	if _, err := algo.ReadJSON(strings.NewReader(`{"vertexes": 1, "edges": [{"from": 0, "to": 1}]}`)); err == nil {
+		t.Error("Edge out of range accepted")
+	}
+	if _, err := algo.ReadJSON(strings.NewReader(`{"vertexes": 100000000}`)); err != algo.ErrTooManyVertexes {
+		t.Error("Huge vertex count accepted", err)
+	}
+}
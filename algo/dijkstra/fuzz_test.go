@@ -0,0 +1,18 @@
+package dijkstra_test
+
+import (
+	"testing"
+
+	algo "github.com/octo47/gomisc/algo/dijkstra"
+	"github.com/octo47/gomisc/algo/dijkstra/pathcheck"
+)
+
+func FuzzDijkstra(f *testing.F) {
+	f.Add([]byte{2, 0, 0, 1, 3, 0, 2, 2, 2, 1, 0})
+	f.Add([]byte{5, 3, 3, 4, 1, 4, 0, 1, 0, 3, 7, 1, 2, 0})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		pathcheck.Verify(t, pathcheck.FromBytes(data), func(g *algo.Graph, source int) (*algo.Path, error) {
+			return g.Dijkstra(source)
+		})
+	})
+}
@@ -0,0 +1,208 @@
+package dijkstra
+
+// Edge is a directed edge as seen by the algorithms of the package. An edge
+// of an undirected graph is seen once from each of its ends.
+type Edge struct {
+	ID   EdgeID
+	From int
+	To   int
+	Cost int
+}
+
+// Interface is the read-only view of a graph every algorithm of the package
+// works on. Vertexes are numbered from 0 to VertexCount()-1.
+type Interface interface {
+	VertexCount() int
+	// Neighbors calls fn for every edge leaving the vertex, until fn
+	// returns false.
+	Neighbors(vertex int, fn func(e Edge) bool)
+	// EdgeCost returns the cost of the cheapest edge between two vertexes,
+	// and false if there is no such edge.
+	EdgeCost(from int, to int) (int, bool)
+}
+
+var (
+	_ Interface = (*Graph)(nil)
+	_ Interface = (*DirectedGraph)(nil)
+	_ Interface = (*UndirectedGraph)(nil)
+)
+
+func (g *Graph) VertexCount() int {
+	return len(g.edges)
+}
+
+func (g *Graph) Neighbors(vertex int, fn func(e Edge) bool) {
+	for _, e := range g.edges[vertex] {
+		if !fn(Edge{ID: e.id, From: vertex, To: e.target, Cost: e.cost}) {
+			return
+		}
+	}
+}
+
+func (g *Graph) EdgeCost(from int, to int) (int, bool) {
+	cost, found := 0, false
+	for _, e := range g.edges[from] {
+		if e.target == to && (!found || e.cost < cost) {
+			cost, found = e.cost, true
+		}
+	}
+	return cost, found
+}
+
+// DirectedGraph is a graph where every edge has a direction.
+type DirectedGraph struct {
+	graph *Graph
+}
+
+func NewDirectedGraph() *DirectedGraph {
+	return &DirectedGraph{graph: NewGraph()}
+}
+
+// add vertex to graph and return its index
+func (g *DirectedGraph) AddVertex() int {
+	return g.graph.AddVertex()
+}
+
+func (g *DirectedGraph) AddVertexes(count int) {
+	g.graph.AddVertexes(count)
+}
+
+// AddEdge adds an edge going from one vertex to another and returns its id.
+func (g *DirectedGraph) AddEdge(from int, to int, cost int) EdgeID {
+	return g.graph.AddEdge(from, to, cost, false)
+}
+
+// DelEdge removes every edge going from one vertex to another and returns
+// how many were removed. Edges going the other way are kept.
+func (g *DirectedGraph) DelEdge(from int, to int) int {
+	return g.graph.DelAllEdges(from, to)
+}
+
+func (g *DirectedGraph) DelEdgeByID(id EdgeID) error {
+	return g.graph.DelEdgeByID(id)
+}
+
+func (g *DirectedGraph) SetEdgeCost(id EdgeID, cost int) error {
+	return g.graph.SetEdgeCost(id, cost)
+}
+
+// OutDegree returns the number of edges leaving the vertex.
+func (g *DirectedGraph) OutDegree(vertex int) int {
+	return len(g.graph.edges[vertex])
+}
+
+func (g *DirectedGraph) VertexCount() int {
+	return g.graph.VertexCount()
+}
+
+func (g *DirectedGraph) Neighbors(vertex int, fn func(e Edge) bool) {
+	g.graph.Neighbors(vertex, fn)
+}
+
+func (g *DirectedGraph) EdgeCost(from int, to int) (int, bool) {
+	return g.graph.EdgeCost(from, to)
+}
+
+func (g *DirectedGraph) CostBounds() (int, int) {
+	return g.graph.CostBounds()
+}
+
+func (g *DirectedGraph) Version() uint64 {
+	return g.graph.Version()
+}
+
+// UndirectedGraph is a graph where every edge can be walked both ways.
+type UndirectedGraph struct {
+	graph *Graph
+}
+
+func NewUndirectedGraph() *UndirectedGraph {
+	return &UndirectedGraph{graph: NewGraph()}
+}
+
+// add vertex to graph and return its index
+func (g *UndirectedGraph) AddVertex() int {
+	return g.graph.AddVertex()
+}
+
+func (g *UndirectedGraph) AddVertexes(count int) {
+	g.graph.AddVertexes(count)
+}
+
+// AddEdge adds an edge between two vertexes and returns its id.
+func (g *UndirectedGraph) AddEdge(vertex1 int, vertex2 int, cost int) EdgeID {
+	return g.graph.AddEdge(vertex1, vertex2, cost, true)
+}
+
+// DelEdge removes every edge between two vertexes and returns how many
+// were removed.
+func (g *UndirectedGraph) DelEdge(vertex1 int, vertex2 int) int {
+	return g.graph.DelAllEdges(vertex1, vertex2)
+}
+
+func (g *UndirectedGraph) DelEdgeByID(id EdgeID) error {
+	return g.graph.DelEdgeByID(id)
+}
+
+func (g *UndirectedGraph) SetEdgeCost(id EdgeID, cost int) error {
+	return g.graph.SetEdgeCost(id, cost)
+}
+
+// Degree returns the number of edges touching the vertex, self-loops
+// counted twice.
+func (g *UndirectedGraph) Degree(vertex int) int {
+	return len(g.graph.edges[vertex])
+}
+
+func (g *UndirectedGraph) VertexCount() int {
+	return g.graph.VertexCount()
+}
+
+func (g *UndirectedGraph) Neighbors(vertex int, fn func(e Edge) bool) {
+	g.graph.Neighbors(vertex, fn)
+}
+
+func (g *UndirectedGraph) EdgeCost(from int, to int) (int, bool) {
+	return g.graph.EdgeCost(from, to)
+}
+
+func (g *UndirectedGraph) CostBounds() (int, int) {
+	return g.graph.CostBounds()
+}
+
+func (g *UndirectedGraph) Version() uint64 {
+	return g.graph.Version()
+}
+
+// ConnectedComponents labels every vertex with the id of its connected
+// component and returns the labels together with the number of components.
+// Components are numbered in order of their lowest vertex.
+func ConnectedComponents(g *UndirectedGraph) ([]int, int) {
+	n := g.VertexCount()
+	comp := make([]int, n)
+	for i := 0; i < n; i++ {
+		comp[i] = Undef
+	}
+	count := 0
+	queue := make([]int, 0)
+	for root := 0; root < n; root++ {
+		if comp[root] != Undef {
+			continue
+		}
+		comp[root] = count
+		queue = append(queue[:0], root)
+		for len(queue) > 0 {
+			u := queue[0]
+			queue = queue[1:]
+			g.Neighbors(u, func(e Edge) bool {
+				if comp[e.To] == Undef {
+					comp[e.To] = count
+					queue = append(queue, e.To)
+				}
+				return true
+			})
+		}
+		count++
+	}
+	return comp, count
+}
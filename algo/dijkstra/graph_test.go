@@ -0,0 +1,94 @@
+package dijkstra_test
+
+import (
+	"reflect"
+	"testing"
+
+	algo "github.com/octo47/gomisc/algo/dijkstra"
+)
+
+func TestDirectedGraph(t *testing.T) {
+	graph := algo.NewDirectedGraph()
+	graph.AddVertexes(3)
+	graph.AddEdge(0, 1, 2)
+	graph.AddEdge(1, 2, 2)
+	graph.AddEdge(2, 0, 1)
+	graph.AddEdge(0, 1, 1)
+
+	if d := graph.OutDegree(0); d != 2 {
+		t.Error("Wrong out degree", d)
+	}
+	if cost, ok := graph.EdgeCost(0, 1); !ok || cost != 1 {
+		t.Error("Wrong edge cost", cost, ok)
+	}
+	if _, ok := graph.EdgeCost(1, 0); ok {
+		t.Error("Edge must not be walked backwards")
+	}
+
+	path, err := algo.Dijkstra(graph, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pathTo0 := path.BuildPath(0); !reflect.DeepEqual(pathTo0, []int{0, 2, 1}) {
+		t.Error("Wrong path calculated", pathTo0)
+	}
+	if _, count := algo.StronglyConnectedComponents(graph); count != 1 {
+		t.Error("Cycle must be a single component", count)
+	}
+
+	if removed := graph.DelEdge(0, 1); removed != 2 {
+		t.Error("Wrong number of removed edges", removed)
+	}
+	if _, count := algo.StronglyConnectedComponents(graph); count != 3 {
+		t.Error("Broken cycle must fall apart", count)
+	}
+}
+
+func TestUndirectedGraph(t *testing.T) {
+	graph := algo.NewUndirectedGraph()
+	graph.AddVertexes(5)
+	graph.AddEdge(0, 1, 4)
+	road := graph.AddEdge(1, 2, 1)
+	graph.AddEdge(3, 3, 1)
+
+	if d := graph.Degree(1); d != 2 {
+		t.Error("Wrong degree", d)
+	}
+	if d := graph.Degree(3); d != 2 {
+		t.Error("Self-loop must be counted twice", d)
+	}
+	if cost, ok := graph.EdgeCost(2, 1); !ok || cost != 1 {
+		t.Error("Edge must be walked both ways", cost, ok)
+	}
+
+	comp, count := algo.ConnectedComponents(graph)
+	if count != 3 || !reflect.DeepEqual(comp, []int{0, 0, 0, 1, 2}) {
+		t.Error("Wrong components", comp, count)
+	}
+
+	path, err := algo.Dijkstra(graph, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cost := path.PathCost(0); cost != 5 {
+		t.Error("Wrong path cost", cost)
+	}
+
+	if err := graph.DelEdgeByID(road); err != nil {
+		t.Fatal(err)
+	}
+	if d := graph.Degree(2); d != 0 {
+		t.Error("Both directions must be removed", d)
+	}
+	if _, count := algo.ConnectedComponents(graph); count != 4 {
+		t.Error("Wrong component count", count)
+	}
+}
+
+func TestDijkstraVertexRange(t *testing.T) {
+	graph := algo.NewDirectedGraph()
+	graph.AddVertexes(2)
+	if _, err := algo.Dijkstra(graph, 2); err != algo.ErrVertexRange {
+		t.Error("Expected range error", err)
+	}
+}
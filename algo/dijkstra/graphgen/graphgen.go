@@ -0,0 +1,148 @@
+// Package graphgen builds synthetic graphs for tests and benchmarks. Every
+// generator takes a seed, so the same arguments always give the same graph.
+package graphgen
+
+import (
+	"math"
+	"math/rand"
+
+	algo "github.com/octo47/gomisc/algo/dijkstra"
+)
+
+// Point is a vertex position on the unit square.
+type Point struct {
+	X float64
+	Y float64
+}
+
+// GeometricScale converts euclidean distances to integer edge costs.
+const GeometricScale = 1000
+
+func randomCost(rnd *rand.Rand, maxCost int) int {
+	if maxCost <= 1 {
+		return 1
+	}
+	return 1 + rnd.Intn(maxCost)
+}
+
+// Grid builds a width x height 4-connected grid with unit costs. Vertex
+// y*width+x is the cell (x, y). Every cell is an obstacle with the given
+// probability; obstacles keep their vertex but have no edges.
+func Grid(width int, height int, obstacles float64, seed int64) *algo.Graph {
+	rnd := rand.New(rand.NewSource(seed))
+	blocked := make([]bool, width*height)
+	for i := range blocked {
+		blocked[i] = rnd.Float64() < obstacles
+	}
+
+	g := algo.NewGraph()
+	g.AddVertexes(width * height)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			v := y*width + x
+			if blocked[v] {
+				continue
+			}
+			if x+1 < width && !blocked[v+1] {
+				g.AddEdge(v, v+1, 1, true)
+			}
+			if y+1 < height && !blocked[v+width] {
+				g.AddEdge(v, v+width, 1, true)
+			}
+		}
+	}
+	return g
+}
+
+// ErdosRenyi builds a directed G(n, p) random graph: every ordered pair of
+// distinct vertices is connected with probability p, with costs drawn
+// uniformly from [1, maxCost].
+func ErdosRenyi(n int, p float64, maxCost int, seed int64) *algo.Graph {
+	rnd := rand.New(rand.NewSource(seed))
+	g := algo.NewGraph()
+	g.AddVertexes(n)
+	for u := 0; u < n; u++ {
+		for v := 0; v < n; v++ {
+			if u != v && rnd.Float64() < p {
+				g.AddEdge(u, v, randomCost(rnd, maxCost), false)
+			}
+		}
+	}
+	return g
+}
+
+// BarabasiAlbert builds an undirected scale-free graph by preferential
+// attachment: every new vertex connects to m distinct existing vertices,
+// picked with probability proportional to their degree. Costs are drawn
+// uniformly from [1, maxCost].
+func BarabasiAlbert(n int, m int, maxCost int, seed int64) *algo.Graph {
+	rnd := rand.New(rand.NewSource(seed))
+	g := algo.NewGraph()
+	g.AddVertexes(n)
+	if m < 1 {
+		return g
+	}
+
+	// every vertex appears here once per incident edge
+	ends := make([]int, 0)
+	seedSize := m + 1
+	if seedSize > n {
+		seedSize = n
+	}
+	for u := 0; u < seedSize; u++ {
+		for v := u + 1; v < seedSize; v++ {
+			g.AddEdge(u, v, randomCost(rnd, maxCost), true)
+			ends = append(ends, u, v)
+		}
+	}
+	for u := seedSize; u < n; u++ {
+		picked := make(map[int]bool)
+		targets := make([]int, 0, m)
+		for len(targets) < m && len(targets) < u {
+			v := ends[rnd.Intn(len(ends))]
+			if !picked[v] {
+				picked[v] = true
+				targets = append(targets, v)
+			}
+		}
+		for _, v := range targets {
+			g.AddEdge(u, v, randomCost(rnd, maxCost), true)
+			ends = append(ends, u, v)
+		}
+	}
+	return g
+}
+
+// RandomGeometric places n vertices uniformly on the unit square and
+// connects, in both directions, every pair closer than radius. Costs are
+// distances scaled by GeometricScale and rounded up, so they are always
+// at least 1.
+func RandomGeometric(n int, radius float64, seed int64) (*algo.Graph, []Point) {
+	rnd := rand.New(rand.NewSource(seed))
+	points := make([]Point, n)
+	for i := range points {
+		points[i] = Point{X: rnd.Float64(), Y: rnd.Float64()}
+	}
+
+	g := algo.NewGraph()
+	g.AddVertexes(n)
+	for u := 0; u < n; u++ {
+		for v := u + 1; v < n; v++ {
+			d := math.Hypot(points[u].X-points[v].X, points[u].Y-points[v].Y)
+			if d < radius {
+				cost := int(math.Ceil(d * GeometricScale))
+				if cost < 1 {
+					cost = 1
+				}
+				g.AddEdge(u, v, cost, true)
+			}
+		}
+	}
+	return g, points
+}
+
+// Complete builds a complete directed graph on n vertices with costs drawn
+// uniformly from [1, maxCost].
+func Complete(n int, maxCost int, seed int64) *algo.Graph {
+	return ErdosRenyi(n, 1, maxCost, seed)
+}
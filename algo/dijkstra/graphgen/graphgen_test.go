@@ -0,0 +1,66 @@
+package graphgen_test
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/octo47/gomisc/algo/dijkstra/graphgen"
+)
+
+func TestGrid(t *testing.T) {
+	g := graphgen.Grid(4, 3, 0, 1)
+	path, err := g.Dijkstra(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cost := path.PathCost(11); cost != 5 {
+		t.Error("Wrong manhattan distance", cost)
+	}
+}
+
+func TestGridObstacles(t *testing.T) {
+	g := graphgen.Grid(10, 10, 1, 1)
+	path, err := g.Dijkstra(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cost := path.PathCost(1); cost != math.MaxInt32 {
+		t.Error("Obstacles must not be connected", cost)
+	}
+}
+
+func TestComplete(t *testing.T) {
+	g := graphgen.Complete(5, 1, 1)
+	path, err := g.Dijkstra(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for v := 1; v < 5; v++ {
+		if cost := path.PathCost(v); cost != 1 {
+			t.Error("Every vertex must be adjacent", v, cost)
+		}
+	}
+}
+
+func TestSeededReproducible(t *testing.T) {
+	a, pa := graphgen.RandomGeometric(200, 0.1, 47)
+	b, pb := graphgen.RandomGeometric(200, 0.1, 47)
+	if !reflect.DeepEqual(pa, pb) || !reflect.DeepEqual(a, b) {
+		t.Error("Same seed must give the same graph")
+	}
+	if !reflect.DeepEqual(graphgen.ErdosRenyi(50, 0.1, 10, 3), graphgen.ErdosRenyi(50, 0.1, 10, 3)) {
+		t.Error("Same seed must give the same graph")
+	}
+	if !reflect.DeepEqual(graphgen.BarabasiAlbert(100, 3, 10, 3), graphgen.BarabasiAlbert(100, 3, 10, 3)) {
+		t.Error("Same seed must give the same graph")
+	}
+}
+
+func TestBarabasiAlbertConnected(t *testing.T) {
+	g := graphgen.BarabasiAlbert(500, 2, 10, 5)
+	_, count := g.StronglyConnectedComponents()
+	if count != 1 {
+		t.Error("Preferential attachment graph must be connected", count)
+	}
+}
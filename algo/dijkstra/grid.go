@@ -0,0 +1,105 @@
+package dijkstra
+
+import "errors"
+
+var ErrGridSize = errors.New("Grid size is out of range")
+
+// GridGraph is an implicit 4-connected grid: its edges are computed on the
+// fly, so grids far too large to build with AddEdge can still be searched.
+// Vertex y*width+x is the cell (x, y).
+type GridGraph struct {
+	width   int
+	height  int
+	maxCost int
+	cost    func(x int, y int) int
+}
+
+var (
+	_ Interface   = (*GridGraph)(nil)
+	_ CostBounder = (*GridGraph)(nil)
+)
+
+// grid moves, their index is stored in edge ids
+var gridMoves = [4][2]int{{1, 0}, {0, 1}, {-1, 0}, {0, -1}}
+
+// NewGridGraph returns a width x height grid where cost tells the price of
+// entering a cell, at most maxCost. Cells with negative cost are walls.
+// Grids whose vertexes or edge ids don't fit an int are rejected with
+// ErrGridSize.
+func NewGridGraph(width int, height int, maxCost int, cost func(x int, y int) int) (*GridGraph, error) {
+	if width < 0 || height < 0 || maxCost < 0 {
+		return nil, ErrGridSize
+	}
+	if width > 0 && height > maxInt/len(gridMoves)/width {
+		return nil, ErrGridSize
+	}
+	return &GridGraph{width: width, height: height, maxCost: maxCost, cost: cost}, nil
+}
+
+// Vertex returns the vertex of the cell (x, y).
+func (g *GridGraph) Vertex(x int, y int) int {
+	return y*g.width + x
+}
+
+// Cell returns the coordinates of the vertex.
+func (g *GridGraph) Cell(vertex int) (int, int) {
+	return vertex % g.width, vertex / g.width
+}
+
+func (g *GridGraph) VertexCount() int {
+	return g.width * g.height
+}
+
+// CostBounds returns the bounds of the cost of entering a cell, walls
+// aside.
+func (g *GridGraph) CostBounds() (int, int) {
+	return 0, g.maxCost
+}
+
+func (g *GridGraph) Neighbors(vertex int, fn func(e Edge) bool) {
+	x, y := g.Cell(vertex)
+	if g.cost(x, y) < 0 {
+		return
+	}
+	for i, m := range gridMoves {
+		nx, ny := x+m[0], y+m[1]
+		if nx < 0 || nx >= g.width || ny < 0 || ny >= g.height {
+			continue
+		}
+		cost := g.cost(nx, ny)
+		if cost < 0 {
+			continue
+		}
+		id := EdgeID(vertex*len(gridMoves) + i)
+		if !fn(Edge{ID: id, From: vertex, To: g.Vertex(nx, ny), Cost: cost}) {
+			return
+		}
+	}
+}
+
+func (g *GridGraph) EdgeCost(from int, to int) (int, bool) {
+	fx, fy := g.Cell(from)
+	tx, ty := g.Cell(to)
+	if abs(fx-tx)+abs(fy-ty) != 1 || g.cost(fx, fy) < 0 {
+		return 0, false
+	}
+	cost := g.cost(tx, ty)
+	return cost, cost >= 0
+}
+
+// Manhattan returns an A* heuristic towards the target, admissible as long
+// as entering any cell costs at least minCost.
+func (g *GridGraph) Manhattan(target int, minCost int) func(vertex int) int {
+	tx, ty := g.Cell(target)
+	return func(vertex int) int {
+		x, y := g.Cell(vertex)
+		return (abs(x-tx) + abs(y-ty)) * minCost
+	}
+}
+
+func abs(a int) int {
+	if a < 0 {
+		return -a
+	}
+	return a
+}
@@ -0,0 +1,114 @@
+package dijkstra_test
+
+import (
+	"testing"
+
+	algo "github.com/octo47/gomisc/algo/dijkstra"
+)
+
+// a maze of walls '#' and cells with entering cost
+var maze = []string{
+	"1111#111",
+	"1##1#1#1",
+	"1#11119#",
+	"1#1##1#1",
+	"1111#111",
+}
+
+func mazeGraph() *algo.GridGraph {
+	grid, err := algo.NewGridGraph(len(maze[0]), len(maze), 9, func(x, y int) int {
+		if c := maze[y][x]; c != '#' {
+			return int(c - '0')
+		}
+		return -1
+	})
+	if err != nil {
+		panic(err)
+	}
+	return grid
+}
+
+func TestGridGraph(t *testing.T) {
+	grid := mazeGraph()
+	source, target := grid.Vertex(0, 0), grid.Vertex(7, 4)
+
+	path, err := algo.Dijkstra(grid, source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cost := path.PathCost(target); cost != 11 {
+		t.Error("Wrong path cost", cost)
+	}
+	if cost := path.PathCost(grid.Vertex(4, 0)); cost != algo.UndefDist {
+		t.Error("Walls must not be reachable", cost)
+	}
+
+	if cost, ok := grid.EdgeCost(grid.Vertex(5, 2), grid.Vertex(6, 2)); !ok || cost != 9 {
+		t.Error("Wrong edge cost", cost, ok)
+	}
+	if _, ok := grid.EdgeCost(grid.Vertex(0, 0), grid.Vertex(1, 1)); ok {
+		t.Error("Diagonal cells are not adjacent")
+	}
+}
+
+func TestGridAStar(t *testing.T) {
+	grid := mazeGraph()
+	source, target := grid.Vertex(0, 0), grid.Vertex(7, 4)
+
+	path, cost, err := algo.AStar(grid, source, target, grid.Manhattan(target, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cost != 11 || path[0] != target || path[len(path)-1] != source {
+		t.Error("Wrong path", path, cost)
+	}
+	sum := 0
+	for i := len(path) - 1; i > 0; i-- {
+		c, ok := grid.EdgeCost(path[i], path[i-1])
+		if !ok {
+			t.Fatal("Path goes through walls", path)
+		}
+		sum += c
+	}
+	if sum != cost {
+		t.Error("Path cost differs from edge costs", sum, cost)
+	}
+
+	if _, _, err := algo.AStar(grid, source, grid.Vertex(4, 0), grid.Manhattan(target, 1)); err != algo.ErrNoPath {
+		t.Error("Expected unreachable error", err)
+	}
+}
+
+func TestGridAStarLarge(t *testing.T) {
+	// far too many edges to build, but A* only touches a strip of cells
+	const side = 1 << 14
+	grid, err := algo.NewGridGraph(side, side, 1, func(x, y int) int { return 1 })
+	if err != nil {
+		t.Fatal(err)
+	}
+	source, target := grid.Vertex(10, 10), grid.Vertex(300, 200)
+	_, cost, err := algo.AStar(grid, source, target, grid.Manhattan(target, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cost != 480 {
+		t.Error("Wrong path cost", cost)
+	}
+}
+
+func TestGridGraphSize(t *testing.T) {
+	one := func(x, y int) int { return 1 }
+	maxInt := int(^uint(0) >> 1)
+	for _, size := range [][3]int{{maxInt, 2, 1}, {maxInt / 4, 2, 1}, {-1, 2, 1}, {2, 2, -1}} {
+		if _, err := algo.NewGridGraph(size[0], size[1], size[2], one); err != algo.ErrGridSize {
+			t.Error("Bad grid accepted", size, err)
+		}
+	}
+	grid, err := algo.NewGridGraph(0, 5, 1, one)
+	if err != nil || grid.VertexCount() != 0 {
+		t.Error("Empty grid rejected", err)
+	}
+	if min, max := mazeGraph().CostBounds(); min != 0 || max != 9 {
+		t.Error("Wrong cost bounds", min, max)
+	}
+}
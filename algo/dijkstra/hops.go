@@ -0,0 +1,183 @@
+package dijkstra
+
+import (
+	"context"
+	"errors"
+	"sort"
+)
+
+var ErrNegativeHops = errors.New("Hop limit must be non-negative")
+
+// HopPath holds the cheapest paths from a source using a bounded number of
+// edges.
+type HopPath struct {
+	source  int
+	maxHops int
+	// changes[v] lists the rounds which made the path to v cheaper, in
+	// order, so rounds cost memory only for the vertexes they change
+	changes [][]hopChange
+}
+
+// hopChange is the cheapest path to a vertex from some number of hops on:
+// its cost and the vertex before the last edge, Undef for the source
+type hopChange struct {
+	hops int
+	dist int
+	prev int
+}
+
+// HopCost is a point of the cost-vs-hops trade-off of a target.
+type HopCost struct {
+	Hops int
+	Cost int
+}
+
+// ShortestPathMaxHops finds the cheapest paths from source using at most
+// maxHops edges, see the package function.
+func (g *Graph) ShortestPathMaxHops(source int, maxHops int) (*HopPath, error) {
+	return ShortestPathMaxHops(g, source, maxHops)
+}
+
+// ShortestPathMaxHops finds the cheapest paths from source to every vertex
+// of g using at most maxHops edges. It runs maxHops rounds of Bellman-Ford,
+// each relaxing the edges of the vertexes improved by the previous round,
+// and keeps the changes of every round to answer queries for fewer hops. Negative costs
+// are fine since the hop bound rules out endless cycles.
+func ShortestPathMaxHops(g Interface, source int, maxHops int) (*HopPath, error) {
+	return ShortestPathMaxHopsContext(context.Background(), g, source, maxHops)
+}
+
+// ShortestPathMaxHopsContext is ShortestPathMaxHops which gives up with
+// ctx.Err() once the context is done. WithProgress reports the number of
+// vertexes whose edges were relaxed, over all rounds. Of the other options
+// only the restrictions are honored, the rest fail with
+// ErrUnsupportedOption.
+func ShortestPathMaxHopsContext(ctx context.Context, g Interface, source int, maxHops int, opts ...Option) (*HopPath, error) {
+	n := g.VertexCount()
+	if source < 0 || source >= n {
+		return nil, ErrVertexRange
+	}
+	if maxHops < 0 {
+		return nil, ErrNegativeHops
+	}
+	o := newOptions(opts)
+	if err := o.only(optProgress | optRestrictions); err != nil {
+		return nil, err
+	}
+	g, err := o.view(g)
+	if err != nil {
+		return nil, err
+	}
+	m, err := newMonitor(ctx, o)
+	if err != nil {
+		return nil, err
+	}
+	p := &HopPath{source: source, maxHops: maxHops, changes: make([][]hopChange, n)}
+	p.changes[source] = []hopChange{{hops: 0, dist: 0, prev: Undef}}
+	dist := make([]int, n)
+	prev := make([]int, n)
+	for i := range dist {
+		dist[i] = UndefDist
+	}
+	dist[source] = 0
+
+	// costs of the active vertexes as of the previous round, the current
+	// one may lower them before their edges are relaxed
+	active := []int{source}
+	last := []int{0}
+	queued := make([]bool, n)
+	for h := 1; h <= maxHops && len(active) > 0; h++ {
+		next := make([]int, 0)
+		for i, u := range active {
+			g.Neighbors(u, func(e Edge) bool {
+				v := e.To
+				if alt := last[i] + e.Cost; alt < dist[v] {
+					dist[v] = alt
+					prev[v] = u
+					if !queued[v] {
+						queued[v] = true
+						next = append(next, v)
+					}
+				}
+				return true
+			})
+			if err := m.settle(); err != nil {
+				return nil, err
+			}
+		}
+		last = last[:0]
+		for _, v := range next {
+			queued[v] = false
+			p.changes[v] = append(p.changes[v], hopChange{hops: h, dist: dist[v], prev: prev[v]})
+			last = append(last, dist[v])
+		}
+		active = next
+	}
+	m.finish()
+	return p, nil
+}
+
+// within returns the cheapest path to target with at most hops edges, and
+// false if there is none
+func (p *HopPath) within(target int, hops int) (hopChange, bool) {
+	if hops > p.maxHops {
+		hops = p.maxHops
+	}
+	changes := p.changes[target]
+	i := sort.Search(len(changes), func(i int) bool { return changes[i].hops > hops })
+	if i == 0 {
+		return hopChange{}, false
+	}
+	return changes[i-1], true
+}
+
+// PathCost returns the cost of the cheapest path to target within the hop
+// limit, UndefDist if there is none.
+func (p *HopPath) PathCost(target int) int {
+	return p.CostWithin(target, p.maxHops)
+}
+
+// CostWithin returns the cost of the cheapest path to target with at most
+// hops edges. Hops beyond the limit of the search count as the limit.
+func (p *HopPath) CostWithin(target int, hops int) int {
+	c, ok := p.within(target, hops)
+	if !ok {
+		return UndefDist
+	}
+	return c.dist
+}
+
+// BuildPath returns the cheapest path within the hop limit, from target
+// back to source like Path.BuildPath, or an empty path if there is none.
+func (p *HopPath) BuildPath(target int) []int {
+	return p.BuildPathWithin(target, p.maxHops)
+}
+
+// BuildPathWithin returns the cheapest path to target with at most hops
+// edges, from target back to source, or an empty path if there is none.
+func (p *HopPath) BuildPathWithin(target int, hops int) []int {
+	path := make([]int, 0)
+	c, ok := p.within(target, hops)
+	if !ok {
+		return path
+	}
+	path = append(path, target)
+	for c.prev != Undef {
+		path = append(path, c.prev)
+		// the rest of the path takes one edge less
+		c, _ = p.within(c.prev, c.hops-1)
+	}
+	return path
+}
+
+// TradeOff returns the cost-vs-hops curve of target: the hop counts at
+// which the cheapest path to target gets cheaper, with the cost it gets
+// down to. The first point is the path with the fewest edges, the last one
+// the cheapest path within the limit.
+func (p *HopPath) TradeOff(target int) []HopCost {
+	curve := make([]HopCost, len(p.changes[target]))
+	for i, c := range p.changes[target] {
+		curve[i] = HopCost{Hops: c.hops, Cost: c.dist}
+	}
+	return curve
+}
@@ -0,0 +1,143 @@
+package dijkstra_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	algo "github.com/octo47/gomisc/algo/dijkstra"
+	"github.com/octo47/gomisc/algo/dijkstra/graphgen"
+)
+
+// a direct expensive link and ever cheaper longer detours
+func hopGraph() *algo.Graph {
+	graph := algo.NewGraph()
+	graph.AddVertexes(6)
+	graph.AddEdge(0, 5, 10, false)
+	graph.AddEdge(0, 1, 3, false)
+	graph.AddEdge(1, 5, 4, false)
+	graph.AddEdge(1, 2, 1, false)
+	graph.AddEdge(2, 3, 1, false)
+	graph.AddEdge(3, 5, 1, false)
+	graph.AddEdge(3, 4, 1, false)
+	return graph
+}
+
+func TestShortestPathMaxHops(t *testing.T) {
+	graph := hopGraph()
+	for _, tc := range []struct {
+		hops int
+		cost int
+		path []int
+	}{
+		{0, algo.UndefDist, []int{}},
+		{1, 10, []int{5, 0}},
+		{2, 7, []int{5, 1, 0}},
+		{3, 7, []int{5, 1, 0}},
+		{4, 6, []int{5, 3, 2, 1, 0}},
+		{9, 6, []int{5, 3, 2, 1, 0}},
+	} {
+		path, err := graph.ShortestPathMaxHops(0, tc.hops)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cost := path.PathCost(5); cost != tc.cost {
+			t.Error(tc.hops, "hops: wrong cost", cost)
+		}
+		if got := path.BuildPath(5); !reflect.DeepEqual(got, tc.path) {
+			t.Error(tc.hops, "hops: wrong path", got)
+		}
+	}
+
+	path, _ := graph.ShortestPathMaxHops(0, 9)
+	want := []algo.HopCost{{Hops: 1, Cost: 10}, {Hops: 2, Cost: 7}, {Hops: 4, Cost: 6}}
+	if curve := path.TradeOff(5); !reflect.DeepEqual(curve, want) {
+		t.Error("Wrong trade-off", curve)
+	}
+	if got := path.BuildPathWithin(5, 3); !reflect.DeepEqual(got, []int{5, 1, 0}) {
+		t.Error("Wrong path within 3 hops", got)
+	}
+	if cost := path.CostWithin(4, 3); cost != algo.UndefDist {
+		t.Error("Vertex 4 is 4 hops away", cost)
+	}
+	if cost := path.CostWithin(0, 0); cost != 0 {
+		t.Error("Wrong source cost", cost)
+	}
+
+	if _, err := graph.ShortestPathMaxHops(0, -1); err != algo.ErrNegativeHops {
+		t.Error("Negative hop limit accepted", err)
+	}
+	if _, err := graph.ShortestPathMaxHops(6, 1); err != algo.ErrVertexRange {
+		t.Error("Bad source accepted", err)
+	}
+}
+
+func TestShortestPathMaxHopsNegativeCycle(t *testing.T) {
+	graph := algo.NewGraph()
+	graph.AddVertexes(3)
+	graph.AddEdge(0, 1, 1, false)
+	graph.AddEdge(1, 2, -3, false)
+	graph.AddEdge(2, 1, 1, false)
+	path, err := graph.ShortestPathMaxHops(0, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cost := path.PathCost(2); cost != -4 {
+		t.Error("Wrong cost around the cycle", cost)
+	}
+	if got := path.BuildPath(2); !reflect.DeepEqual(got, []int{2, 1, 2, 1, 0}) {
+		t.Error("Wrong path around the cycle", got)
+	}
+}
+
+func TestShortestPathMaxHopsUnbounded(t *testing.T) {
+	graph := graphgen.ErdosRenyi(60, 0.1, 20, 5)
+	want, _ := graph.Dijkstra(0)
+	got, _ := graph.ShortestPathMaxHops(0, graph.VertexCount())
+	for v := 0; v < graph.VertexCount(); v++ {
+		if got.PathCost(v) != want.PathCost(v) {
+			t.Error("Vertex", v, "costs", got.PathCost(v), "want", want.PathCost(v))
+		}
+		if cost := pathCost(graph, got.BuildPath(v)); want.PathCost(v) != algo.UndefDist && cost != want.PathCost(v) {
+			t.Error("Path to", v, "costs", cost)
+		}
+	}
+}
+
+// pathCost sums the cheapest edges along a path built from target back to
+// source
+func pathCost(g algo.Interface, path []int) int {
+	cost := 0
+	for i := len(path) - 1; i > 0; i-- {
+		c, _ := g.EdgeCost(path[i], path[i-1])
+		cost += c
+	}
+	return cost
+}
+
+func TestShortestPathMaxHopsContext(t *testing.T) {
+	graph := hopGraph()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := algo.ShortestPathMaxHopsContext(ctx, graph, 0, 3); err != context.Canceled {
+		t.Error("Search must stop", err)
+	}
+
+	relaxed := 0
+	path, err := algo.ShortestPathMaxHopsContext(context.Background(), graph, 0, 9,
+		algo.WithForbiddenVertexes(1),
+		algo.WithProgress(func(n int) { relaxed = n }),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cost := path.PathCost(5); cost != 10 {
+		t.Error("Forbidden vertex used", cost)
+	}
+	if relaxed != 2 {
+		t.Error("Wrong progress", relaxed)
+	}
+	if _, err := algo.ShortestPathMaxHopsContext(context.Background(), graph, 0, 1, algo.WithQueue(algo.QueueDial)); err != algo.ErrUnsupportedOption {
+		t.Error("Option silently ignored", err)
+	}
+}
@@ -0,0 +1,235 @@
+package dijkstra
+
+import (
+	"context"
+	"errors"
+)
+
+// maxInt is the largest int on the platform
+const maxInt = int(^uint(0) >> 1)
+
+var (
+	ErrVertexRange      = errors.New("Vertex is out of range")
+	ErrSameSourceSink   = errors.New("Source and sink must differ")
+	ErrNegativeCapacity = errors.New("Edge capacity must be non-negative")
+)
+
+// EdgeFlow is the flow pushed through a single edge of the graph.
+type EdgeFlow struct {
+	ID       EdgeID
+	From     int
+	To       int
+	Capacity int
+	Flow     int
+}
+
+// Flow is a maximum flow between two vertices together with the minimum cut
+// that limits it.
+type Flow struct {
+	Value int
+	// Edges holds the flow of every edge, in the order the graph lists
+	// them: by source vertex, then by neighbor order. Both directions of an
+	// undirected edge are listed, under the same id.
+	Edges      []EdgeFlow
+	sourceSide []bool
+}
+
+// residual is a residual network where arc a and a^1 are the forward and
+// backward halves of the same edge.
+type residual struct {
+	head  [][]int
+	to    []int
+	cap   []int
+	level []int
+	iter  []int
+	path  []int
+}
+
+func newResidual(n int) *residual {
+	return &residual{
+		head:  make([][]int, n),
+		to:    make([]int, 0),
+		cap:   make([]int, 0),
+		level: make([]int, n),
+		iter:  make([]int, n),
+		path:  make([]int, 0),
+	}
+}
+
+func (r *residual) addArc(from int, to int, capacity int) int {
+	a := len(r.to)
+	r.to = append(r.to, to, from)
+	r.cap = append(r.cap, capacity, 0)
+	r.head[from] = append(r.head[from], a)
+	r.head[to] = append(r.head[to], a+1)
+	return a
+}
+
+// bfs builds the level graph and reports whether sink is still reachable
+func (r *residual) bfs(source int, sink int) bool {
+	for i := range r.level {
+		r.level[i] = Undef
+	}
+	r.level[source] = 0
+	queue := []int{source}
+	for len(queue) > 0 {
+		u := queue[0]
+		queue = queue[1:]
+		for _, a := range r.head[u] {
+			v := r.to[a]
+			if r.cap[a] > 0 && r.level[v] == Undef {
+				r.level[v] = r.level[u] + 1
+				queue = append(queue, v)
+			}
+		}
+	}
+	return r.level[sink] != Undef
+}
+
+// augment pushes flow along one path of the level graph and returns its
+// value, or 0 when the blocking flow is complete. The walk is iterative so
+// long paths don't grow the goroutine stack.
+func (r *residual) augment(source int, sink int) int {
+	r.path = r.path[:0]
+	u := source
+	for {
+		if u == sink {
+			f := maxInt
+			for _, a := range r.path {
+				if r.cap[a] < f {
+					f = r.cap[a]
+				}
+			}
+			for _, a := range r.path {
+				r.cap[a] -= f
+				r.cap[a^1] += f
+			}
+			return f
+		}
+		advanced := false
+		for ; r.iter[u] < len(r.head[u]); r.iter[u]++ {
+			a := r.head[u][r.iter[u]]
+			if v := r.to[a]; r.cap[a] > 0 && r.level[v] == r.level[u]+1 {
+				r.path = append(r.path, a)
+				u = v
+				advanced = true
+				break
+			}
+		}
+		if advanced {
+			continue
+		}
+		if u == source {
+			return 0
+		}
+		// dead end, never come back here during this phase
+		r.level[u] = Undef
+		a := r.path[len(r.path)-1]
+		r.path = r.path[:len(r.path)-1]
+		u = r.to[a^1]
+		r.iter[u]++
+	}
+}
+
+// MaxFlow computes the maximum flow from source to sink, see the package
+// function.
+func (g *Graph) MaxFlow(source int, sink int) (*Flow, error) {
+	return MaxFlow(g, source, sink)
+}
+
+// MaxFlow computes the maximum flow from source to sink with Dinic's
+// algorithm, using edge costs as capacities. An undirected edge is treated
+// as two independent arcs of the same capacity.
+func MaxFlow(g Interface, source int, sink int) (*Flow, error) {
+	return MaxFlowContext(context.Background(), g, source, sink)
+}
+
+// MaxFlowContext is MaxFlow which gives up with ctx.Err() once the context
+// is done. WithProgress reports the number of augmenting paths found, the
+// other options fail with ErrUnsupportedOption.
+func MaxFlowContext(ctx context.Context, g Interface, source int, sink int, opts ...Option) (*Flow, error) {
+	o := newOptions(opts)
+	if err := o.only(optProgress); err != nil {
+		return nil, err
+	}
+	m, err := newMonitor(ctx, o)
+	if err != nil {
+		return nil, err
+	}
+	n := g.VertexCount()
+	if source < 0 || source >= n || sink < 0 || sink >= n {
+		return nil, ErrVertexRange
+	}
+	if source == sink {
+		return nil, ErrSameSourceSink
+	}
+
+	r := newResidual(n)
+	flow := &Flow{Edges: make([]EdgeFlow, 0)}
+	arcs := make([]int, 0)
+	negative := false
+	for u := 0; u < n; u++ {
+		g.Neighbors(u, func(e Edge) bool {
+			if e.Cost < 0 {
+				negative = true
+				return false
+			}
+			arcs = append(arcs, r.addArc(u, e.To, e.Cost))
+			flow.Edges = append(flow.Edges, EdgeFlow{ID: e.ID, From: u, To: e.To, Capacity: e.Cost})
+			return true
+		})
+		if negative {
+			return nil, ErrNegativeCapacity
+		}
+	}
+
+	for r.bfs(source, sink) {
+		for i := range r.iter {
+			r.iter[i] = 0
+		}
+		for f := r.augment(source, sink); f > 0; f = r.augment(source, sink) {
+			flow.Value += f
+			if err := m.settle(); err != nil {
+				return nil, err
+			}
+		}
+	}
+	m.finish()
+
+	for i, a := range arcs {
+		flow.Edges[i].Flow = flow.Edges[i].Capacity - r.cap[a]
+	}
+	// the last bfs marked everything still reachable from source
+	flow.sourceSide = make([]bool, n)
+	for v := 0; v < n; v++ {
+		flow.sourceSide[v] = r.level[v] != Undef
+	}
+	return flow, nil
+}
+
+// MinCut returns the vertex partition of the minimum cut: vertices reachable
+// from source in the residual network, and all the others.
+func (f *Flow) MinCut() ([]int, []int) {
+	source := make([]int, 0)
+	sink := make([]int, 0)
+	for v, s := range f.sourceSide {
+		if s {
+			source = append(source, v)
+		} else {
+			sink = append(sink, v)
+		}
+	}
+	return source, sink
+}
+
+// CutEdges returns the edges crossing the minimum cut from the source side
+// to the sink side. Their capacities sum up to the flow value.
+func (f *Flow) CutEdges() []EdgeFlow {
+	cut := make([]EdgeFlow, 0)
+	for _, e := range f.Edges {
+		if f.sourceSide[e.From] && !f.sourceSide[e.To] {
+			cut = append(cut, e)
+		}
+	}
+	return cut
+}
@@ -0,0 +1,79 @@
+package dijkstra_test
+
+import (
+	"reflect"
+	"testing"
+
+	algo "github.com/octo47/gomisc/algo/dijkstra"
+)
+
+func TestMaxFlow(t *testing.T) {
+	// classic CLRS network, maximum flow is 23
+	graph := algo.NewGraph()
+	graph.AddVertexes(6)
+	graph.AddEdge(0, 1, 16, false)
+	graph.AddEdge(0, 2, 13, false)
+	graph.AddEdge(2, 1, 4, false)
+	graph.AddEdge(1, 3, 12, false)
+	graph.AddEdge(3, 2, 9, false)
+	graph.AddEdge(2, 4, 14, false)
+	graph.AddEdge(4, 3, 7, false)
+	graph.AddEdge(3, 5, 20, false)
+	graph.AddEdge(4, 5, 4, false)
+
+	flow, err := graph.MaxFlow(0, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if flow.Value != 23 {
+		t.Fatal("Wrong flow value", flow.Value)
+	}
+
+	balance := make([]int, 6)
+	for _, e := range flow.Edges {
+		if e.Flow < 0 || e.Flow > e.Capacity {
+			t.Error("Flow violates capacity", e)
+		}
+		balance[e.From] -= e.Flow
+		balance[e.To] += e.Flow
+	}
+	if !reflect.DeepEqual(balance, []int{-23, 0, 0, 0, 0, 23}) {
+		t.Error("Flow is not conserved", balance)
+	}
+
+	source, sink := flow.MinCut()
+	if !reflect.DeepEqual(source, []int{0, 1, 2, 4}) || !reflect.DeepEqual(sink, []int{3, 5}) {
+		t.Error("Wrong min cut", source, sink)
+	}
+	capacity := 0
+	for _, e := range flow.CutEdges() {
+		capacity += e.Capacity
+	}
+	if capacity != flow.Value {
+		t.Error("Cut capacity differs from flow", capacity)
+	}
+}
+
+func TestMaxFlowBidirAndErrors(t *testing.T) {
+	graph := algo.NewGraph()
+	graph.AddVertexes(4)
+	graph.AddEdge(0, 1, 3, true)
+	graph.AddEdge(0, 2, 2, true)
+	graph.AddEdge(1, 2, 5, true)
+	graph.AddEdge(1, 3, 2, true)
+	graph.AddEdge(2, 3, 3, true)
+
+	flow, err := graph.MaxFlow(0, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if flow.Value != 5 {
+		t.Error("Wrong flow value", flow.Value)
+	}
+	if _, err := graph.MaxFlow(1, 1); err != algo.ErrSameSourceSink {
+		t.Error("Expected same source error", err)
+	}
+	if _, err := graph.MaxFlow(0, 4); err != algo.ErrVertexRange {
+		t.Error("Expected range error", err)
+	}
+}
@@ -0,0 +1,272 @@
+package dijkstra
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	ErrNegativeCycle = errors.New("Graph has a negative cost cycle")
+	ErrRaggedMatrix  = errors.New("Cost matrix rows must have equal length")
+	ErrNegativeLimit = errors.New("Flow limit must be non-negative")
+)
+
+// FlowGraph is a capacitated graph where every edge has both a capacity and
+// a per-unit cost of the flow pushed through it.
+type FlowGraph struct {
+	n    int
+	from []int
+	to   []int
+	cap  []int
+	cost []int
+}
+
+// CostFlow is a flow between two vertices together with its total cost.
+type CostFlow struct {
+	Value int
+	Cost  int
+	// Edges holds the flow of every edge, indexed by the id returned
+	// from FlowGraph.AddEdge.
+	Edges []EdgeFlow
+}
+
+func NewFlowGraph() *FlowGraph {
+	return &FlowGraph{
+		from: make([]int, 0),
+		to:   make([]int, 0),
+		cap:  make([]int, 0),
+		cost: make([]int, 0),
+	}
+}
+
+// add vertex to graph and return its index
+func (f *FlowGraph) AddVertex() int {
+	f.n++
+	return f.n - 1
+}
+
+func (f *FlowGraph) AddVertexes(count int) {
+	f.n += count
+}
+
+// AddEdge adds a directed edge and returns its id.
+func (f *FlowGraph) AddEdge(from int, to int, capacity int, cost int) (EdgeID, error) {
+	if from < 0 || from >= f.n || to < 0 || to >= f.n {
+		return EdgeID(Undef), ErrVertexRange
+	}
+	if capacity < 0 {
+		return EdgeID(Undef), ErrNegativeCapacity
+	}
+	f.from = append(f.from, from)
+	f.to = append(f.to, to)
+	f.cap = append(f.cap, capacity)
+	f.cost = append(f.cost, cost)
+	return EdgeID(len(f.from) - 1), nil
+}
+
+// MinCostMaxFlow finds the maximum flow from source to sink with the
+// minimal total cost.
+func (f *FlowGraph) MinCostMaxFlow(source int, sink int) (*CostFlow, error) {
+	return f.MinCostFlowContext(context.Background(), source, sink, maxInt)
+}
+
+// MinCostMaxFlowContext is MinCostMaxFlow which gives up with ctx.Err()
+// once the context is done, see MinCostFlowContext.
+func (f *FlowGraph) MinCostMaxFlowContext(ctx context.Context, source int, sink int, opts ...Option) (*CostFlow, error) {
+	return f.MinCostFlowContext(ctx, source, sink, maxInt, opts...)
+}
+
+// MinCostFlow pushes up to limit units of flow from source to sink at the
+// minimal total cost, using successive shortest paths. Every path is found
+// by Dijkstra on the residual network with costs reduced by vertex
+// potentials, which keeps them non-negative. Initial potentials come from
+// Bellman-Ford, so negative edge costs are allowed as long as there are no
+// negative cycles.
+func (f *FlowGraph) MinCostFlow(source int, sink int, limit int) (*CostFlow, error) {
+	return f.MinCostFlowContext(context.Background(), source, sink, limit)
+}
+
+// MinCostFlowContext is MinCostFlow which gives up with ctx.Err() once the
+// context is done. WithProgress reports the number of augmenting paths
+// found, the other options fail with ErrUnsupportedOption.
+func (f *FlowGraph) MinCostFlowContext(ctx context.Context, source int, sink int, limit int, opts ...Option) (*CostFlow, error) {
+	o := newOptions(opts)
+	if err := o.only(optProgress); err != nil {
+		return nil, err
+	}
+	monitor, err := newMonitor(ctx, o)
+	if err != nil {
+		return nil, err
+	}
+	if source < 0 || source >= f.n || sink < 0 || sink >= f.n {
+		return nil, ErrVertexRange
+	}
+	if source == sink {
+		return nil, ErrSameSourceSink
+	}
+	if limit < 0 {
+		return nil, ErrNegativeLimit
+	}
+
+	// residual arcs: 2*i is the edge i, 2*i+1 is its reverse
+	m := len(f.from)
+	to := make([]int, 2*m)
+	rcap := make([]int, 2*m)
+	rcost := make([]int, 2*m)
+	out := make([][]int, f.n)
+	for i := 0; i < m; i++ {
+		to[2*i], to[2*i+1] = f.to[i], f.from[i]
+		rcap[2*i] = f.cap[i]
+		rcost[2*i], rcost[2*i+1] = f.cost[i], -f.cost[i]
+		out[f.from[i]] = append(out[f.from[i]], 2*i)
+		out[f.to[i]] = append(out[f.to[i]], 2*i+1)
+	}
+
+	pot, err := f.potentials()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &CostFlow{}
+	for result.Value < limit {
+		// reduced costs are non-negative, so plain Dijkstra applies
+		residual := NewGraph()
+		residual.AddVertexes(f.n)
+		for u := 0; u < f.n; u++ {
+			for _, a := range out[u] {
+				if rcap[a] > 0 {
+					residual.AddEdge(u, to[a], rcost[a]+pot[u]-pot[to[a]], false)
+				}
+			}
+		}
+		path, err := residual.DijkstraContext(ctx, source)
+		if err != nil {
+			return nil, err
+		}
+		if path.PathCost(sink) == UndefDist {
+			break
+		}
+
+		// pick the cheapest residual arc between consecutive path vertices,
+		// it is the one Dijkstra relaxed
+		arcs := make([]int, 0)
+		push := limit - result.Value
+		for v := sink; v != source; v = path.prev[v] {
+			u := path.prev[v]
+			best := Undef
+			for _, a := range out[u] {
+				if to[a] != v || rcap[a] == 0 {
+					continue
+				}
+				if best == Undef || rcost[a] < rcost[best] {
+					best = a
+				}
+			}
+			arcs = append(arcs, best)
+			if rcap[best] < push {
+				push = rcap[best]
+			}
+		}
+		for _, a := range arcs {
+			rcap[a] -= push
+			rcap[a^1] += push
+			result.Cost += push * rcost[a]
+		}
+		result.Value += push
+		if err := monitor.settle(); err != nil {
+			return nil, err
+		}
+
+		reached := 0
+		for v := 0; v < f.n; v++ {
+			if d := path.PathCost(v); d != UndefDist && d > reached {
+				reached = d
+			}
+		}
+		for v := 0; v < f.n; v++ {
+			if d := path.PathCost(v); d != UndefDist {
+				pot[v] += d
+			} else {
+				pot[v] += reached
+			}
+		}
+	}
+
+	monitor.finish()
+	result.Edges = make([]EdgeFlow, m)
+	for i := 0; i < m; i++ {
+		result.Edges[i] = EdgeFlow{ID: EdgeID(i), From: f.from[i], To: f.to[i], Capacity: f.cap[i], Flow: rcap[2*i+1]}
+	}
+	return result, nil
+}
+
+// potentials runs Bellman-Ford from a virtual source connected to every
+// vertex, over edges with positive capacity.
+func (f *FlowGraph) potentials() ([]int, error) {
+	pot := make([]int, f.n)
+	for round := 0; round <= f.n; round++ {
+		changed := false
+		for i := range f.from {
+			if f.cap[i] == 0 {
+				continue
+			}
+			if alt := pot[f.from[i]] + f.cost[i]; alt < pot[f.to[i]] {
+				pot[f.to[i]] = alt
+				changed = true
+			}
+		}
+		if !changed {
+			return pot, nil
+		}
+	}
+	return nil, ErrNegativeCycle
+}
+
+// Assign solves the assignment problem for a rows x columns cost matrix:
+// every row gets a distinct column so that the total cost is minimal. It
+// returns the column assigned to every row, or Undef for rows left out when
+// there are more rows than columns, and the total cost.
+func Assign(costMatrix [][]int) ([]int, int, error) {
+	rows := len(costMatrix)
+	if rows == 0 {
+		return []int{}, 0, nil
+	}
+	cols := len(costMatrix[0])
+	for _, row := range costMatrix {
+		if len(row) != cols {
+			return nil, 0, ErrRaggedMatrix
+		}
+	}
+
+	// source, rows, columns, sink
+	f := NewFlowGraph()
+	source := f.AddVertex()
+	f.AddVertexes(rows + cols)
+	sink := f.AddVertex()
+	cells := make([][]EdgeID, rows)
+	for r := 0; r < rows; r++ {
+		f.AddEdge(source, 1+r, 1, 0)
+		cells[r] = make([]EdgeID, cols)
+		for c := 0; c < cols; c++ {
+			cells[r][c], _ = f.AddEdge(1+r, 1+rows+c, 1, costMatrix[r][c])
+		}
+	}
+	for c := 0; c < cols; c++ {
+		f.AddEdge(1+rows+c, sink, 1, 0)
+	}
+
+	flow, err := f.MinCostMaxFlow(source, sink)
+	if err != nil {
+		return nil, 0, err
+	}
+	assignment := make([]int, rows)
+	for r := 0; r < rows; r++ {
+		assignment[r] = Undef
+		for c := 0; c < cols; c++ {
+			if flow.Edges[cells[r][c]].Flow > 0 {
+				assignment[r] = c
+			}
+		}
+	}
+	return assignment, flow.Cost, nil
+}
@@ -0,0 +1,118 @@
+package dijkstra_test
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+
+	algo "github.com/octo47/gomisc/algo/dijkstra"
+)
+
+func TestMinCostFlow(t *testing.T) {
+	graph := algo.NewFlowGraph()
+	graph.AddVertexes(4)
+	graph.AddEdge(0, 1, 2, 1)
+	graph.AddEdge(0, 2, 1, 2)
+	graph.AddEdge(1, 2, 1, 1)
+	graph.AddEdge(1, 3, 1, 3)
+	graph.AddEdge(2, 3, 2, 1)
+
+	// two units go 0-1-2-3 and 0-2-3 for 3 each, the last one 0-1-3 for 4
+	flow, err := graph.MinCostMaxFlow(0, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if flow.Value != 3 || flow.Cost != 10 {
+		t.Error("Wrong flow", flow.Value, flow.Cost)
+	}
+
+	flow, err = graph.MinCostFlow(0, 3, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if flow.Value != 1 || flow.Cost != 3 {
+		t.Error("Wrong limited flow", flow.Value, flow.Cost)
+	}
+	if flow.Edges[4].Flow != 1 || flow.Edges[3].Flow != 0 {
+		t.Error("Wrong edge flows", flow.Edges)
+	}
+}
+
+func TestMinCostFlowNegativeCycle(t *testing.T) {
+	graph := algo.NewFlowGraph()
+	graph.AddVertexes(3)
+	graph.AddEdge(0, 1, 1, 1)
+	graph.AddEdge(1, 2, 1, -3)
+	graph.AddEdge(2, 1, 1, 1)
+	if _, err := graph.MinCostMaxFlow(0, 2); err != algo.ErrNegativeCycle {
+		t.Error("Expected negative cycle error", err)
+	}
+}
+
+func TestAssign(t *testing.T) {
+	assignment, cost, err := algo.Assign([][]int{
+		{4, 1, 3},
+		{2, 0, 5},
+		{3, 2, 2},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cost != 5 || !reflect.DeepEqual(assignment, []int{1, 0, 2}) {
+		t.Error("Wrong assignment", assignment, cost)
+	}
+
+	if _, _, err := algo.Assign([][]int{{1, 2}, {3}}); err != algo.ErrRaggedMatrix {
+		t.Error("Expected ragged matrix error", err)
+	}
+}
+
+func TestAssignRandom(t *testing.T) {
+	rnd := rand.New(rand.NewSource(47))
+	for round := 0; round < 50; round++ {
+		n := 1 + rnd.Intn(6)
+		matrix := make([][]int, n)
+		for r := range matrix {
+			matrix[r] = make([]int, n)
+			for c := range matrix[r] {
+				matrix[r][c] = rnd.Intn(40) - 10
+			}
+		}
+		assignment, cost, err := algo.Assign(matrix)
+		if err != nil {
+			t.Fatal(err)
+		}
+		used := make(map[int]bool)
+		sum := 0
+		for r, c := range assignment {
+			if used[c] {
+				t.Fatal("Column assigned twice", assignment)
+			}
+			used[c] = true
+			sum += matrix[r][c]
+		}
+		if sum != cost || cost != bruteAssign(matrix, 0, make([]bool, n)) {
+			t.Fatal("Assignment is not optimal", matrix, assignment, cost)
+		}
+	}
+}
+
+func bruteAssign(matrix [][]int, row int, used []bool) int {
+	if row == len(matrix) {
+		return 0
+	}
+	best := 0
+	found := false
+	for c := range used {
+		if used[c] {
+			continue
+		}
+		used[c] = true
+		if v := matrix[row][c] + bruteAssign(matrix, row+1, used); !found || v < best {
+			best = v
+			found = true
+		}
+		used[c] = false
+	}
+	return best
+}
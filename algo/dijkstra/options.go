@@ -0,0 +1,79 @@
+package dijkstra
+
+import "errors"
+
+var ErrUnsupportedOption = errors.New("Option is not supported by this search")
+
+// Option tunes a single search.
+type Option func(*options)
+
+type options struct {
+	predecessors bool
+	progress     func(settled int)
+	visitor      Visitor
+	queue        Queue
+	restrict     *restrictions
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// optionKind tells options apart for searches which honor only some
+type optionKind int
+
+const (
+	optPredecessors optionKind = 1 << iota
+	optProgress
+	optVisitor
+	optQueue
+	optRestrictions
+)
+
+// only returns ErrUnsupportedOption if an option other than the given
+// kinds was set
+func (o *options) only(kinds optionKind) error {
+	given := optionKind(0)
+	if o.predecessors {
+		given |= optPredecessors
+	}
+	if o.progress != nil {
+		given |= optProgress
+	}
+	if o.visitor != nil {
+		given |= optVisitor
+	}
+	if o.queue != QueueAuto {
+		given |= optQueue
+	}
+	if o.restrict != nil {
+		given |= optRestrictions
+	}
+	if given&^kinds != 0 {
+		return ErrUnsupportedOption
+	}
+	return nil
+}
+
+// WithPredecessors makes the search record every predecessor lying on a
+// shortest path instead of just one, so equal-cost paths can be counted and
+// enumerated. Costs must be non-negative. A zero-cost edge found after its
+// target was settled is recorded too, unless it would close a cycle of
+// zero-cost edges, which is then cut at that edge.
+func WithPredecessors() Option {
+	return func(o *options) {
+		o.predecessors = true
+	}
+}
+
+// WithProgress makes the search report the number of vertexes settled so
+// far, every few thousand vertexes and once more when it is done.
+func WithProgress(fn func(settled int)) Option {
+	return func(o *options) {
+		o.progress = fn
+	}
+}
@@ -0,0 +1,240 @@
+package dijkstra
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+)
+
+var ErrCriteria = errors.New("Edge must have a cost for every criterion")
+
+type criteriaEdge struct {
+	target int
+	costs  []int
+}
+
+// CriteriaGraph is a directed graph where every edge has a vector of costs,
+// one per criterion, like travel time and toll.
+type CriteriaGraph struct {
+	edges    [][]criteriaEdge
+	criteria int
+	count    int
+}
+
+// ParetoPath is a path with its cost for every criterion. Vertexes run
+// from target back to source, like Path.BuildPath.
+type ParetoPath struct {
+	Costs    []int
+	Vertexes []int
+}
+
+func NewCriteriaGraph(criteria int) *CriteriaGraph {
+	return &CriteriaGraph{
+		edges:    make([][]criteriaEdge, 0),
+		criteria: criteria,
+	}
+}
+
+// add vertex to graph and return its index
+func (g *CriteriaGraph) AddVertex() int {
+	g.edges = append(g.edges, make([]criteriaEdge, 0))
+	return len(g.edges) - 1
+}
+
+func (g *CriteriaGraph) AddVertexes(count int) {
+	for i := 0; i < count; i++ {
+		g.edges = append(g.edges, make([]criteriaEdge, 0))
+	}
+}
+
+// AddEdge adds a directed edge with one non-negative cost per criterion
+// and returns its id.
+func (g *CriteriaGraph) AddEdge(from int, to int, costs ...int) (EdgeID, error) {
+	if len(costs) != g.criteria {
+		return EdgeID(Undef), ErrCriteria
+	}
+	for _, c := range costs {
+		if c < 0 {
+			return EdgeID(Undef), ErrNegativeCost
+		}
+	}
+	cp := make([]int, len(costs))
+	copy(cp, costs)
+	g.edges[from] = append(g.edges[from], criteriaEdge{target: to, costs: cp})
+	g.count++
+	return EdgeID(g.count - 1), nil
+}
+
+// ParetoPaths returns every Pareto-optimal path from source to target: no
+// other path is at least as good in every criterion and better in one.
+// Paths with identical costs are reported once. The result is sorted
+// lexicographically by costs.
+func (g *CriteriaGraph) ParetoPaths(source int, target int) ([]ParetoPath, error) {
+	return g.ParetoPathsContext(context.Background(), source, target)
+}
+
+// ParetoPathsContext is ParetoPaths which gives up with ctx.Err() once the
+// context is done, which matters since the number of Pareto-optimal labels
+// can grow exponentially. WithProgress reports the number of labels
+// settled, the other options fail with ErrUnsupportedOption.
+func (g *CriteriaGraph) ParetoPathsContext(ctx context.Context, source int, target int, opts ...Option) ([]ParetoPath, error) {
+	return g.search(ctx, source, target, Undef, 0, opts)
+}
+
+// ConstrainedShortestPath finds the path from source to target with the
+// least cost in criterion minimize, among paths whose cost in criterion
+// bound doesn't exceed limit. It returns ErrNoPath if no path fits.
+func (g *CriteriaGraph) ConstrainedShortestPath(source int, target int, minimize int, bound int, limit int) (ParetoPath, error) {
+	return g.ConstrainedShortestPathContext(context.Background(), source, target, minimize, bound, limit)
+}
+
+// ConstrainedShortestPathContext is ConstrainedShortestPath which gives up
+// with ctx.Err() once the context is done, see ParetoPathsContext.
+func (g *CriteriaGraph) ConstrainedShortestPathContext(ctx context.Context, source int, target int, minimize int, bound int, limit int, opts ...Option) (ParetoPath, error) {
+	if minimize < 0 || minimize >= g.criteria || bound < 0 || bound >= g.criteria {
+		return ParetoPath{}, ErrCriteria
+	}
+	paths, err := g.search(ctx, source, target, bound, limit, opts)
+	if err != nil {
+		return ParetoPath{}, err
+	}
+	if len(paths) == 0 {
+		return ParetoPath{}, ErrNoPath
+	}
+	best := 0
+	for i := range paths {
+		if paths[i].Costs[minimize] < paths[best].Costs[minimize] {
+			best = i
+		}
+	}
+	return paths[best], nil
+}
+
+type label struct {
+	vertex int
+	costs  []int
+	parent int
+}
+
+// search is the label-setting algorithm of Martins: labels are settled in
+// lexicographic order, so a settled label which no other settled label at
+// its vertex dominates is Pareto-optimal. Labels exceeding limit in
+// criterion bound are dropped when bound is set.
+func (g *CriteriaGraph) search(ctx context.Context, source int, target int, bound int, limit int, opts []Option) ([]ParetoPath, error) {
+	n := len(g.edges)
+	if source < 0 || source >= n || target < 0 || target >= n {
+		return nil, ErrVertexRange
+	}
+	o := newOptions(opts)
+	if err := o.only(optProgress); err != nil {
+		return nil, err
+	}
+	m, err := newMonitor(ctx, o)
+	if err != nil {
+		return nil, err
+	}
+
+	labels := []label{{vertex: source, costs: make([]int, g.criteria), parent: Undef}}
+	settled := make([][]int, n)
+	queue := &labelQueue{labels: &labels}
+	heap.Push(queue, 0)
+
+	dominated := func(costs []int, vertex int) bool {
+		for _, l := range settled[vertex] {
+			if dominates(labels[l].costs, costs) {
+				return true
+			}
+		}
+		// costs never decrease, so anything the target has beaten is done
+		for _, l := range settled[target] {
+			if dominates(labels[l].costs, costs) {
+				return true
+			}
+		}
+		return false
+	}
+
+	for queue.Len() > 0 {
+		l := heap.Pop(queue).(int)
+		u := labels[l].vertex
+		if dominated(labels[l].costs, u) {
+			continue
+		}
+		settled[u] = append(settled[u], l)
+		if u == target {
+			continue
+		}
+		for _, e := range g.edges[u] {
+			costs := make([]int, g.criteria)
+			for i := range costs {
+				costs[i] = labels[l].costs[i] + e.costs[i]
+			}
+			if bound != Undef && costs[bound] > limit {
+				continue
+			}
+			if dominated(costs, e.target) {
+				continue
+			}
+			labels = append(labels, label{vertex: e.target, costs: costs, parent: l})
+			heap.Push(queue, len(labels)-1)
+		}
+		if err := m.settle(); err != nil {
+			return nil, err
+		}
+	}
+	m.finish()
+
+	paths := make([]ParetoPath, 0, len(settled[target]))
+	for _, l := range settled[target] {
+		p := ParetoPath{Costs: labels[l].costs, Vertexes: make([]int, 0)}
+		for at := l; at != Undef; at = labels[at].parent {
+			p.Vertexes = append(p.Vertexes, labels[at].vertex)
+		}
+		paths = append(paths, p)
+	}
+	return paths, nil
+}
+
+// dominates reports whether a is at least as good as b in every criterion
+func dominates(a []int, b []int) bool {
+	for i := range a {
+		if a[i] > b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// labelQueue is a min-heap of label indexes in lexicographic cost order
+type labelQueue struct {
+	labels *[]label
+	items  []int
+}
+
+func (q *labelQueue) Len() int {
+	return len(q.items)
+}
+
+func (q *labelQueue) Less(i, j int) bool {
+	a, b := (*q.labels)[q.items[i]].costs, (*q.labels)[q.items[j]].costs
+	for k := range a {
+		if a[k] != b[k] {
+			return a[k] < b[k]
+		}
+	}
+	return false
+}
+
+func (q *labelQueue) Swap(i, j int) {
+	q.items[i], q.items[j] = q.items[j], q.items[i]
+}
+
+func (q *labelQueue) Push(x interface{}) {
+	q.items = append(q.items, x.(int))
+}
+
+func (q *labelQueue) Pop() interface{} {
+	item := q.items[len(q.items)-1]
+	q.items = q.items[:len(q.items)-1]
+	return item
+}
@@ -0,0 +1,93 @@
+package dijkstra_test
+
+import (
+	"reflect"
+	"testing"
+
+	algo "github.com/octo47/gomisc/algo/dijkstra"
+)
+
+const (
+	travelTime = 0
+	toll       = 1
+)
+
+func tollRoads() *algo.CriteriaGraph {
+	graph := algo.NewCriteriaGraph(2)
+	graph.AddVertexes(4)
+	// motorway 0-1-3 is fast and expensive, country road 0-2-3 slow and
+	// free, the bridge 0-3 is in between; the ferry 0-3 and the detour
+	// 0-2-1-3 are dominated by the bridge
+	graph.AddEdge(0, 1, 1, 5)
+	graph.AddEdge(1, 3, 1, 5)
+	graph.AddEdge(0, 2, 5, 0)
+	graph.AddEdge(2, 3, 5, 0)
+	graph.AddEdge(0, 3, 4, 3)
+	graph.AddEdge(0, 3, 5, 4)
+	graph.AddEdge(2, 1, 1, 0)
+	return graph
+}
+
+func TestParetoPaths(t *testing.T) {
+	paths, err := tollRoads().ParetoPaths(0, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []algo.ParetoPath{
+		{Costs: []int{2, 10}, Vertexes: []int{3, 1, 0}},
+		{Costs: []int{4, 3}, Vertexes: []int{3, 0}},
+		{Costs: []int{10, 0}, Vertexes: []int{3, 2, 0}},
+	}
+	if !reflect.DeepEqual(paths, want) {
+		t.Error("Wrong Pareto set", paths)
+	}
+}
+
+func TestConstrainedShortestPath(t *testing.T) {
+	graph := tollRoads()
+	cases := []struct {
+		limit int
+		costs []int
+	}{
+		{100, []int{2, 10}},
+		{9, []int{4, 3}},
+		{2, []int{10, 0}},
+	}
+	for _, c := range cases {
+		path, err := graph.ConstrainedShortestPath(0, 3, travelTime, toll, c.limit)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(path.Costs, c.costs) {
+			t.Error("Wrong constrained path", c.limit, path)
+		}
+	}
+	if _, err := graph.ConstrainedShortestPath(0, 3, travelTime, toll, -1); err != algo.ErrNoPath {
+		t.Error("Expected no path", err)
+	}
+}
+
+func TestParetoSingleCriterion(t *testing.T) {
+	graph := algo.NewCriteriaGraph(1)
+	plain := algo.NewGraph()
+	graph.AddVertexes(5)
+	plain.AddVertexes(5)
+	for _, e := range [][3]int{{0, 1, 4}, {0, 2, 1}, {2, 1, 2}, {1, 3, 1}, {2, 3, 5}, {3, 4, 3}} {
+		graph.AddEdge(e[0], e[1], e[2])
+		plain.AddEdge(e[0], e[1], e[2], false)
+	}
+	path, _ := plain.Dijkstra(0)
+	for target := 0; target < 5; target++ {
+		paths, err := graph.ParetoPaths(0, target)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(paths) != 1 || paths[0].Costs[0] != path.PathCost(target) {
+			t.Error("Single criterion must give the shortest path", target, paths)
+		}
+	}
+
+	if _, err := graph.AddEdge(0, 1, 1, 2); err != algo.ErrCriteria {
+		t.Error("Expected criteria error", err)
+	}
+}
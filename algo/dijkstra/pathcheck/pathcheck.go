@@ -0,0 +1,258 @@
+// Package pathcheck is a property testing harness for shortest path
+// searches. It generates random graphs, checks a search against a
+// brute-force Bellman-Ford baseline, and shrinks failing graphs to minimal
+// reproducers.
+package pathcheck
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"testing"
+
+	algo "github.com/octo47/gomisc/algo/dijkstra"
+)
+
+// Edge is a directed edge of a test case.
+type Edge struct {
+	From int
+	To   int
+	Cost int
+}
+
+// Case is a graph with a chosen source vertex. Unlike algo.Graph it keeps
+// its edges visible, so checks can be computed independently of the search.
+type Case struct {
+	Vertexes int
+	Source   int
+	Edges    []Edge
+}
+
+// Solver is a shortest path search under test.
+type Solver func(g *algo.Graph, source int) (*algo.Path, error)
+
+// Graph builds the graph of the case.
+func (c Case) Graph() *algo.Graph {
+	g := algo.NewGraph()
+	g.AddVertexes(c.Vertexes)
+	for _, e := range c.Edges {
+		g.AddEdge(e.From, e.To, e.Cost, false)
+	}
+	return g
+}
+
+// String prints the case as Go code building it.
+func (c Case) String() string {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "pathcheck.Case{Vertexes: %d, Source: %d, Edges: []pathcheck.Edge{", c.Vertexes, c.Source)
+	for i, e := range c.Edges {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		fmt.Fprintf(&b, "{%d, %d, %d}", e.From, e.To, e.Cost)
+	}
+	b.WriteString("}}")
+	return b.String()
+}
+
+// Random generates a case with up to maxVertexes vertexes and maxEdges
+// edges with costs in [0, maxCost].
+func Random(rnd *rand.Rand, maxVertexes int, maxEdges int, maxCost int) Case {
+	c := Case{Vertexes: 1 + rnd.Intn(maxVertexes)}
+	c.Source = rnd.Intn(c.Vertexes)
+	m := rnd.Intn(maxEdges + 1)
+	c.Edges = make([]Edge, m)
+	for i := range c.Edges {
+		c.Edges[i] = Edge{From: rnd.Intn(c.Vertexes), To: rnd.Intn(c.Vertexes), Cost: rnd.Intn(maxCost + 1)}
+	}
+	return c
+}
+
+// FromBytes decodes arbitrary bytes into a case, for fuzzing. The first byte
+// selects the vertex count, the second the source, and every following
+// triple is an edge.
+func FromBytes(data []byte) Case {
+	c := Case{Vertexes: 1, Edges: make([]Edge, 0)}
+	if len(data) > 0 {
+		c.Vertexes = 1 + int(data[0])%64
+	}
+	if len(data) > 1 {
+		c.Source = int(data[1]) % c.Vertexes
+	}
+	for i := 2; i+2 < len(data); i += 3 {
+		c.Edges = append(c.Edges, Edge{
+			From: int(data[i]) % c.Vertexes,
+			To:   int(data[i+1]) % c.Vertexes,
+			Cost: int(data[i+2]),
+		})
+	}
+	return c
+}
+
+// BellmanFord computes the baseline distances of the case, UndefDist for
+// unreachable vertexes.
+func BellmanFord(c Case) []int {
+	dist := make([]int, c.Vertexes)
+	for i := range dist {
+		dist[i] = algo.UndefDist
+	}
+	dist[c.Source] = 0
+	for round := 1; round < c.Vertexes; round++ {
+		changed := false
+		for _, e := range c.Edges {
+			if dist[e.From] == algo.UndefDist {
+				continue
+			}
+			if alt := dist[e.From] + e.Cost; alt < dist[e.To] {
+				dist[e.To] = alt
+				changed = true
+			}
+		}
+		if !changed {
+			break
+		}
+	}
+	return dist
+}
+
+// Check runs the solver on the case and verifies that distances match
+// Bellman-Ford, that no edge violates the triangle inequality, and that
+// every path reported by BuildPath is made of real edges whose costs add up
+// to PathCost. Costs are expected to be non-negative.
+func Check(c Case, solve Solver) error {
+	path, err := solve(c.Graph(), c.Source)
+	if err != nil {
+		return fmt.Errorf("solver failed: %v", err)
+	}
+
+	want := BellmanFord(c)
+	for v := 0; v < c.Vertexes; v++ {
+		if got := path.PathCost(v); got != want[v] {
+			return fmt.Errorf("distance to %d is %d, want %d", v, got, want[v])
+		}
+	}
+
+	for _, e := range c.Edges {
+		du, dv := path.PathCost(e.From), path.PathCost(e.To)
+		if du != algo.UndefDist && dv > du+e.Cost {
+			return fmt.Errorf("edge %d->%d violates triangle inequality: %d > %d+%d", e.From, e.To, dv, du, e.Cost)
+		}
+	}
+
+	// cheapest edge between every pair, as paths may use parallel edges
+	cheapest := make(map[[2]int]int)
+	for _, e := range c.Edges {
+		k := [2]int{e.From, e.To}
+		if cost, ok := cheapest[k]; !ok || e.Cost < cost {
+			cheapest[k] = e.Cost
+		}
+	}
+	for v := 0; v < c.Vertexes; v++ {
+		if want[v] == algo.UndefDist {
+			continue
+		}
+		// BuildPath lists vertexes from target back to source
+		p := path.BuildPath(v)
+		if len(p) == 0 || p[0] != v || p[len(p)-1] != c.Source {
+			return fmt.Errorf("path to %d is %v, must run from %d to %d", v, p, c.Source, v)
+		}
+		sum := 0
+		for i := len(p) - 1; i > 0; i-- {
+			cost, ok := cheapest[[2]int{p[i], p[i-1]}]
+			if !ok {
+				return fmt.Errorf("path to %d is %v, there is no edge %d->%d", v, p, p[i], p[i-1])
+			}
+			sum += cost
+		}
+		if sum != path.PathCost(v) {
+			return fmt.Errorf("path to %d is %v with cost %d, PathCost is %d", v, p, sum, path.PathCost(v))
+		}
+	}
+	return nil
+}
+
+// Shrink greedily simplifies a case for which fails reports true: it drops
+// edges, drops vertexes and lowers costs for as long as the case keeps
+// failing. The result is a local minimum: removing any single edge or
+// vertex, or lowering any cost, makes the failure go away.
+func Shrink(c Case, fails func(Case) bool) Case {
+	for changed := true; changed; {
+		changed = false
+
+		for i := 0; i < len(c.Edges); i++ {
+			edges := make([]Edge, 0, len(c.Edges)-1)
+			edges = append(edges, c.Edges[:i]...)
+			edges = append(edges, c.Edges[i+1:]...)
+			if next := (Case{Vertexes: c.Vertexes, Source: c.Source, Edges: edges}); fails(next) {
+				c = next
+				changed = true
+				i--
+			}
+		}
+
+		for v := c.Vertexes - 1; v >= 0; v-- {
+			if v == c.Source || c.Vertexes == 1 {
+				continue
+			}
+			if next := dropVertex(c, v); fails(next) {
+				c = next
+				changed = true
+			}
+		}
+
+		for i := range c.Edges {
+			for _, cost := range []int{0, 1, c.Edges[i].Cost / 2} {
+				if cost >= c.Edges[i].Cost {
+					continue
+				}
+				next := Case{Vertexes: c.Vertexes, Source: c.Source, Edges: make([]Edge, len(c.Edges))}
+				copy(next.Edges, c.Edges)
+				next.Edges[i].Cost = cost
+				if fails(next) {
+					c = next
+					changed = true
+					break
+				}
+			}
+		}
+	}
+	return c
+}
+
+// dropVertex removes the vertex with its edges and renumbers the rest
+func dropVertex(c Case, v int) Case {
+	renumber := func(u int) int {
+		if u > v {
+			return u - 1
+		}
+		return u
+	}
+	next := Case{Vertexes: c.Vertexes - 1, Source: renumber(c.Source), Edges: make([]Edge, 0, len(c.Edges))}
+	for _, e := range c.Edges {
+		if e.From != v && e.To != v {
+			next.Edges = append(next.Edges, Edge{From: renumber(e.From), To: renumber(e.To), Cost: e.Cost})
+		}
+	}
+	return next
+}
+
+// Run checks the solver against rounds random cases generated from seed and
+// fails the test with a shrunk reproducer on the first failure.
+func Run(t testing.TB, solve Solver, rounds int, seed int64) {
+	rnd := rand.New(rand.NewSource(seed))
+	for round := 0; round < rounds; round++ {
+		c := Random(rnd, 30, 120, 20)
+		Verify(t, c, solve)
+	}
+}
+
+// Verify checks a single case and fails the test with a shrunk reproducer
+// when it doesn't hold.
+func Verify(t testing.TB, c Case, solve Solver) {
+	err := Check(c, solve)
+	if err == nil {
+		return
+	}
+	min := Shrink(c, func(c Case) bool { return Check(c, solve) != nil })
+	t.Fatalf("%v\nminimal reproducer: %v\nfails with: %v", err, min, Check(min, solve))
+}
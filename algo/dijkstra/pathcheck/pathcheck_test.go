@@ -0,0 +1,61 @@
+package pathcheck_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	algo "github.com/octo47/gomisc/algo/dijkstra"
+	"github.com/octo47/gomisc/algo/dijkstra/pathcheck"
+)
+
+func dijkstra(g *algo.Graph, source int) (*algo.Path, error) {
+	return g.Dijkstra(source)
+}
+
+func TestDijkstraAgreesWithBellmanFord(t *testing.T) {
+	pathcheck.Run(t, dijkstra, 500, 47)
+}
+
+func TestCheckDetectsWrongDistances(t *testing.T) {
+	broken := func(g *algo.Graph, source int) (*algo.Path, error) {
+		return nil, errors.New("broken")
+	}
+	c := pathcheck.Case{Vertexes: 2, Edges: []pathcheck.Edge{{0, 1, 3}}}
+	if pathcheck.Check(c, broken) == nil {
+		t.Error("Solver error must be reported")
+	}
+
+	// cheaper edges confuse a search which only keeps the first parallel edge
+	c = pathcheck.Case{Vertexes: 3, Edges: []pathcheck.Edge{{0, 1, 5}, {0, 1, 1}, {1, 2, 1}}}
+	firstEdge := func(g *algo.Graph, source int) (*algo.Path, error) {
+		first := pathcheck.Case{Vertexes: 3, Edges: []pathcheck.Edge{{0, 1, 5}, {1, 2, 1}}}
+		return first.Graph().Dijkstra(source)
+	}
+	if pathcheck.Check(c, firstEdge) == nil {
+		t.Fatal("Wrong distances must be reported")
+	}
+}
+
+func TestShrink(t *testing.T) {
+	c := pathcheck.Case{Vertexes: 5, Source: 0, Edges: []pathcheck.Edge{
+		{0, 1, 7}, {1, 2, 4}, {2, 3, 9}, {3, 4, 2}, {0, 4, 8},
+	}}
+	// pretend the search breaks whenever vertex 3 is reachable at all
+	fails := func(c pathcheck.Case) bool {
+		return c.Vertexes > 3 && pathcheck.BellmanFord(c)[3] != algo.UndefDist
+	}
+	min := pathcheck.Shrink(c, fails)
+	want := pathcheck.Case{Vertexes: 4, Source: 0, Edges: []pathcheck.Edge{{0, 1, 0}, {1, 2, 0}, {2, 3, 0}}}
+	if !reflect.DeepEqual(min, want) {
+		t.Error("Wrong shrunk case", min)
+	}
+}
+
+func TestFromBytes(t *testing.T) {
+	c := pathcheck.FromBytes([]byte{3, 1, 0, 1, 5, 1, 2, 6, 9})
+	want := pathcheck.Case{Vertexes: 4, Source: 1, Edges: []pathcheck.Edge{{0, 1, 5}, {1, 2, 6}}}
+	if !reflect.DeepEqual(c, want) {
+		t.Error("Wrong decoded case", c)
+	}
+}
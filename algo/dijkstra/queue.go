@@ -0,0 +1,314 @@
+package dijkstra
+
+import (
+	"errors"
+	"math/bits"
+)
+
+var ErrCostBounds = errors.New("Edge cost is outside the cost bounds of the graph")
+
+// Queue selects the priority queue Dijkstra settles vertexes with.
+type Queue int
+
+const (
+	// QueueAuto picks the queue from the edge costs: the linear scan when
+	// some are negative, Dial's buckets when all are small, and the radix
+	// heap otherwise.
+	QueueAuto Queue = iota
+	// QueueLinear scans every vertex for the closest one, O(V^2). It is
+	// the only queue which tolerates negative costs.
+	QueueLinear
+	// QueueBinaryHeap is a binary heap, O((V+E) log V). Costs must be
+	// non-negative.
+	QueueBinaryHeap
+	// QueueDial is Dial's circular array of buckets, one per distance,
+	// O(V+E+D) for the largest distance D. Costs must be non-negative and
+	// within the cost bounds of the graph, see CostBounder.
+	QueueDial
+	// QueueRadix is a radix heap, O(E + V log C) for the largest cost C.
+	// Costs must be non-negative.
+	QueueRadix
+)
+
+// dialMaxCost is the largest edge cost QueueAuto still uses Dial's
+// buckets for
+const dialMaxCost = 1 << 12
+
+// WithQueue selects the priority queue of the search instead of picking
+// it from the edge costs.
+func WithQueue(q Queue) Option {
+	return func(o *options) {
+		o.queue = q
+	}
+}
+
+// CostBounder is implemented by graphs which know bounds of their edge
+// costs without scanning them. Bounds may be loose but must hold for every
+// edge: Dial's buckets are sized by the maximum, and a search meeting a
+// costlier edge fails with ErrCostBounds.
+type CostBounder interface {
+	CostBounds() (min int, max int)
+}
+
+// costBounds asks the graph for cost bounds, scanning every edge if the
+// graph can't tell
+func costBounds(g Interface) (int, int) {
+	if b, ok := g.(CostBounder); ok {
+		return b.CostBounds()
+	}
+	min, max := 0, 0
+	for u := 0; u < g.VertexCount(); u++ {
+		g.Neighbors(u, func(e Edge) bool {
+			if e.Cost < min {
+				min = e.Cost
+			}
+			if e.Cost > max {
+				max = e.Cost
+			}
+			return true
+		})
+	}
+	return min, max
+}
+
+// vertexQueue hands out vertexes in order of distance. Queues may return
+// a vertex more than once, the search skips those already settled.
+type vertexQueue interface {
+	push(vertex int, dist int)
+	// pop returns Undef once nothing reachable is left
+	pop() int
+	// only the linear scan takes negative costs, searches on the other
+	// queues fail with ErrNegativeCost
+	negativeCosts() bool
+	// maxCost is the costliest edge the queue can take
+	maxCost() int
+}
+
+func newVertexQueue(q Queue, g Interface, dist []int, visited []bool) vertexQueue {
+	min, max := 0, 0
+	if q == QueueAuto || q == QueueDial {
+		min, max = costBounds(g)
+	}
+	if max < 0 {
+		// all costs are negative, the search rejects them
+		max = 0
+	}
+	if q == QueueAuto {
+		switch {
+		case min < 0:
+			q = QueueLinear
+		case max <= dialMaxCost:
+			q = QueueDial
+		default:
+			q = QueueRadix
+		}
+	}
+	switch q {
+	case QueueBinaryHeap:
+		return &binaryQueue{}
+	case QueueDial:
+		return newDialQueue(max)
+	case QueueRadix:
+		return &radixQueue{}
+	}
+	return &linearQueue{dist: dist, visited: visited}
+}
+
+// linearQueue is the plain O(V) scan over all vertexes
+type linearQueue struct {
+	dist    []int
+	visited []bool
+}
+
+func (q *linearQueue) push(vertex int, dist int) {}
+
+func (q *linearQueue) pop() int {
+	u := Undef
+	for j := range q.dist {
+		if q.visited[j] {
+			continue
+		}
+		if u == Undef || q.dist[j] < q.dist[u] {
+			u = j
+		}
+	}
+	if u == Undef || q.dist[u] == UndefDist {
+		return Undef
+	}
+	return u
+}
+
+func (q *linearQueue) maxCost() int {
+	return maxInt
+}
+
+func (q *linearQueue) negativeCosts() bool {
+	return true
+}
+
+type queueItem struct {
+	vertex   int
+	priority int
+}
+
+// minHeap is a binary min-heap of vertexes which, unlike container/heap,
+// doesn't box its items
+type minHeap []queueItem
+
+func (h *minHeap) push(vertex int, priority int) {
+	*h = append(*h, queueItem{vertex: vertex, priority: priority})
+	a := *h
+	for i := len(a) - 1; i > 0; {
+		parent := (i - 1) / 2
+		if a[parent].priority <= a[i].priority {
+			break
+		}
+		a[parent], a[i] = a[i], a[parent]
+		i = parent
+	}
+}
+
+func (h *minHeap) pop() queueItem {
+	a := *h
+	top := a[0]
+	last := len(a) - 1
+	a[0] = a[last]
+	a = a[:last]
+	for i := 0; ; {
+		child := 2*i + 1
+		if child >= len(a) {
+			break
+		}
+		if child+1 < len(a) && a[child+1].priority < a[child].priority {
+			child++
+		}
+		if a[i].priority <= a[child].priority {
+			break
+		}
+		a[i], a[child] = a[child], a[i]
+		i = child
+	}
+	*h = a
+	return top
+}
+
+type binaryQueue struct {
+	items minHeap
+}
+
+func (q *binaryQueue) push(vertex int, dist int) {
+	q.items.push(vertex, dist)
+}
+
+func (q *binaryQueue) pop() int {
+	if len(q.items) == 0 {
+		return Undef
+	}
+	return q.items.pop().vertex
+}
+
+func (q *binaryQueue) maxCost() int {
+	return maxInt
+}
+
+func (q *binaryQueue) negativeCosts() bool {
+	return false
+}
+
+// dialQueue keeps a bucket per distance in a circular array. While a
+// vertex at distance d is settled every queued distance lies in
+// [d, d+maxCost], so maxCost+1 buckets never collide.
+type dialQueue struct {
+	buckets [][]queueItem
+	cur     int
+	count   int
+}
+
+func newDialQueue(maxCost int) *dialQueue {
+	return &dialQueue{buckets: make([][]queueItem, maxCost+1)}
+}
+
+func (q *dialQueue) push(vertex int, dist int) {
+	b := dist % len(q.buckets)
+	q.buckets[b] = append(q.buckets[b], queueItem{vertex: vertex, priority: dist})
+	q.count++
+}
+
+func (q *dialQueue) pop() int {
+	for q.count > 0 {
+		b := &q.buckets[q.cur%len(q.buckets)]
+		if l := len(*b); l > 0 {
+			item := (*b)[l-1]
+			*b = (*b)[:l-1]
+			q.count--
+			return item.vertex
+		}
+		q.cur++
+	}
+	return Undef
+}
+
+func (q *dialQueue) maxCost() int {
+	return len(q.buckets) - 1
+}
+
+func (q *dialQueue) negativeCosts() bool {
+	return false
+}
+
+// radixQueue is a radix heap: bucket i holds keys which first differ from
+// the last popped key at bit i-1, bucket 0 the keys equal to it
+type radixQueue struct {
+	buckets [65][]queueItem
+	last    int
+	count   int
+}
+
+func (q *radixQueue) bucket(dist int) int {
+	return bits.Len64(uint64(dist ^ q.last))
+}
+
+func (q *radixQueue) push(vertex int, dist int) {
+	b := q.bucket(dist)
+	q.buckets[b] = append(q.buckets[b], queueItem{vertex: vertex, priority: dist})
+	q.count++
+}
+
+func (q *radixQueue) pop() int {
+	if q.count == 0 {
+		return Undef
+	}
+	if len(q.buckets[0]) == 0 {
+		i := 1
+		for len(q.buckets[i]) == 0 {
+			i++
+		}
+		// the smallest key becomes the last one and the bucket spreads out
+		// over lower buckets
+		items := q.buckets[i]
+		q.last = items[0].priority
+		for _, item := range items[1:] {
+			if item.priority < q.last {
+				q.last = item.priority
+			}
+		}
+		q.buckets[i] = items[:0]
+		for _, item := range items {
+			b := q.bucket(item.priority)
+			q.buckets[b] = append(q.buckets[b], item)
+		}
+	}
+	b := q.buckets[0]
+	item := b[len(b)-1]
+	q.buckets[0] = b[:len(b)-1]
+	q.count--
+	return item.vertex
+}
+
+func (q *radixQueue) maxCost() int {
+	return maxInt
+}
+
+func (q *radixQueue) negativeCosts() bool {
+	return false
+}
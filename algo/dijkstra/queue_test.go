@@ -0,0 +1,102 @@
+package dijkstra_test
+
+import (
+	"testing"
+
+	algo "github.com/octo47/gomisc/algo/dijkstra"
+	"github.com/octo47/gomisc/algo/dijkstra/graphgen"
+	"github.com/octo47/gomisc/algo/dijkstra/pathcheck"
+)
+
+var queues = map[string]algo.Queue{
+	"Auto":       algo.QueueAuto,
+	"Linear":     algo.QueueLinear,
+	"BinaryHeap": algo.QueueBinaryHeap,
+	"Dial":       algo.QueueDial,
+	"Radix":      algo.QueueRadix,
+}
+
+func TestQueuesAgreeWithBellmanFord(t *testing.T) {
+	for name, q := range queues {
+		q := q
+		t.Run(name, func(t *testing.T) {
+			pathcheck.Run(t, func(g *algo.Graph, source int) (*algo.Path, error) {
+				return g.Dijkstra(source, algo.WithQueue(q))
+			}, 300, 5)
+		})
+	}
+}
+
+func TestQueuesLargeCosts(t *testing.T) {
+	graph, _ := graphgen.RandomGeometric(500, 0.1, 9)
+	want, _ := graph.Dijkstra(0, algo.WithQueue(algo.QueueLinear))
+	for name, q := range queues {
+		path, err := graph.Dijkstra(0, algo.WithQueue(q))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for v := 0; v < graph.VertexCount(); v++ {
+			if path.PathCost(v) != want.PathCost(v) {
+				t.Fatal("Queue disagrees with linear scan", name, v)
+			}
+		}
+	}
+}
+
+func TestQueuesNegativeCost(t *testing.T) {
+	graph := algo.NewGraph()
+	graph.AddVertexes(3)
+	graph.AddEdge(0, 1, 3, false)
+	graph.AddEdge(1, 2, -2, false)
+	for _, q := range []algo.Queue{algo.QueueBinaryHeap, algo.QueueDial, algo.QueueRadix} {
+		if _, err := graph.Dijkstra(0, algo.WithQueue(q)); err != algo.ErrNegativeCost {
+			t.Error("Heaps must reject negative costs", q, err)
+		}
+	}
+	// a graph with only negative costs has a negative maximum
+	negative := algo.NewGraph()
+	negative.AddVertexes(2)
+	negative.AddEdge(0, 1, -1, false)
+	if _, err := algo.Dijkstra(negativeBounds{negative}, 0, algo.WithQueue(algo.QueueDial)); err != algo.ErrNegativeCost {
+		t.Error("Dial must reject negative bounds", err)
+	}
+	if path, err := graph.Dijkstra(0); err != nil || path.PathCost(2) != 1 {
+		t.Error("Negative costs must fall back to the linear scan", err)
+	}
+}
+
+// negativeBounds claims all costs are -1
+type negativeBounds struct {
+	*algo.Graph
+}
+
+func (negativeBounds) CostBounds() (int, int) {
+	return -1, -1
+}
+
+// looseBounds claims all costs are below 2
+type looseBounds struct {
+	*algo.Graph
+}
+
+func (looseBounds) CostBounds() (int, int) {
+	return 0, 1
+}
+
+func TestDialWrongBounds(t *testing.T) {
+	graph := graphgen.ErdosRenyi(100, 0.05, 500, 3)
+	if _, err := algo.Dijkstra(looseBounds{graph}, 0, algo.WithQueue(algo.QueueDial)); err != algo.ErrCostBounds {
+		t.Error("Dial must reject costs above the bounds", err)
+	}
+	// the other queues don't depend on the bounds
+	want, _ := graph.Dijkstra(0, algo.WithQueue(algo.QueueLinear))
+	path, err := algo.Dijkstra(looseBounds{graph}, 0, algo.WithQueue(algo.QueueRadix))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for v := 0; v < graph.VertexCount(); v++ {
+		if path.PathCost(v) != want.PathCost(v) {
+			t.Fatal("Radix heap must ignore the bounds", v)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package dijkstra
+
+import (
+	"errors"
+	"math"
+)
+
+var ErrCostFactor = errors.New("Cost multiplier must be a non-negative number")
+
+// WithForbiddenVertexes makes the search treat the vertexes as missing
+// together with their edges. A forbidden source is still settled but not
+// left.
+func WithForbiddenVertexes(vertexes ...int) Option {
+	return func(o *options) {
+		r := o.restrictions()
+		for _, v := range vertexes {
+			r.vertexes[v] = true
+		}
+	}
+}
+
+// WithForbiddenEdges makes the search treat the edges as missing, in both
+// directions for edges added with bidir.
+func WithForbiddenEdges(ids ...EdgeID) Option {
+	return func(o *options) {
+		r := o.restrictions()
+		for _, id := range ids {
+			r.edges[id] = true
+		}
+	}
+}
+
+// WithCostOverrides makes the search use the given costs for the edges
+// instead of their own. The map is copied.
+func WithCostOverrides(costs map[EdgeID]int) Option {
+	return func(o *options) {
+		r := o.restrictions()
+		for id, cost := range costs {
+			r.costs[id] = cost
+		}
+	}
+}
+
+// WithCostMultipliers makes the search multiply the costs of the edges by
+// the given non-negative factors, rounding to the nearest integer. An
+// override of the same edge is multiplied too. The map is copied. The
+// search fails with ErrCostFactor on a negative, infinite or NaN factor.
+func WithCostMultipliers(factors map[EdgeID]float64) Option {
+	return func(o *options) {
+		r := o.restrictions()
+		for id, factor := range factors {
+			r.factors[id] = factor
+		}
+	}
+}
+
+type restrictions struct {
+	vertexes map[int]bool
+	edges    map[EdgeID]bool
+	costs    map[EdgeID]int
+	factors  map[EdgeID]float64
+}
+
+func (o *options) restrictions() *restrictions {
+	if o.restrict == nil {
+		o.restrict = &restrictions{
+			vertexes: make(map[int]bool),
+			edges:    make(map[EdgeID]bool),
+			costs:    make(map[EdgeID]int),
+			factors:  make(map[EdgeID]float64),
+		}
+	}
+	return o.restrict
+}
+
+// view returns g as the search should see it, g itself unless some
+// restrictions were given
+func (o *options) view(g Interface) (Interface, error) {
+	if o.restrict == nil {
+		return g, nil
+	}
+	for _, factor := range o.restrict.factors {
+		// NaN compares false too
+		if !(factor >= 0) || math.IsInf(factor, 1) {
+			return nil, ErrCostFactor
+		}
+	}
+	return &restrictedGraph{Interface: g, r: o.restrict}, nil
+}
+
+// restrictedGraph hides forbidden vertexes and edges of a graph and changes
+// the costs of the others, leaving the graph itself alone
+type restrictedGraph struct {
+	Interface
+	r *restrictions
+}
+
+func (g *restrictedGraph) Neighbors(vertex int, fn func(e Edge) bool) {
+	if g.r.vertexes[vertex] {
+		return
+	}
+	g.Interface.Neighbors(vertex, func(e Edge) bool {
+		if g.r.vertexes[e.To] || g.r.edges[e.ID] {
+			return true
+		}
+		e.Cost = g.r.cost(e)
+		return fn(e)
+	})
+}
+
+func (g *restrictedGraph) EdgeCost(from int, to int) (int, bool) {
+	cost, found := 0, false
+	g.Neighbors(from, func(e Edge) bool {
+		if e.To == to && (!found || e.Cost < cost) {
+			cost, found = e.Cost, true
+		}
+		return true
+	})
+	return cost, found
+}
+
+// CostBounds widens the bounds of the graph by the overridden and
+// multiplied costs, so the queue can still be picked without a scan.
+func (g *restrictedGraph) CostBounds() (int, int) {
+	min, max := costBounds(g.Interface)
+	widen := func(cost int) {
+		if cost < min {
+			min = cost
+		}
+		if cost > max {
+			max = cost
+		}
+	}
+	for _, cost := range g.r.costs {
+		widen(cost)
+	}
+	lo, hi := min, max
+	for _, factor := range g.r.factors {
+		widen(scaleCost(lo, factor))
+		widen(scaleCost(hi, factor))
+	}
+	return min, max
+}
+
+func (r *restrictions) cost(e Edge) int {
+	cost := e.Cost
+	if c, ok := r.costs[e.ID]; ok {
+		cost = c
+	}
+	if factor, ok := r.factors[e.ID]; ok {
+		cost = scaleCost(cost, factor)
+	}
+	return cost
+}
+
+func scaleCost(cost int, factor float64) int {
+	return int(math.Round(float64(cost) * factor))
+}
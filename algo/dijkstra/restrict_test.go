@@ -0,0 +1,141 @@
+package dijkstra_test
+
+import (
+	"math"
+	"sync"
+	"testing"
+
+	algo "github.com/octo47/gomisc/algo/dijkstra"
+)
+
+// two ways from 0 to 3: over the bridge 1-2 or around through 4
+func bridgeGraph() (*algo.Graph, algo.EdgeID) {
+	graph := algo.NewGraph()
+	graph.AddVertexes(5)
+	graph.AddEdge(0, 1, 1, true)
+	bridge := graph.AddEdge(1, 2, 1, true)
+	graph.AddEdge(2, 3, 1, true)
+	graph.AddEdge(0, 4, 5, true)
+	graph.AddEdge(4, 3, 5, true)
+	return graph, bridge
+}
+
+func TestForbiddenEdges(t *testing.T) {
+	graph, bridge := bridgeGraph()
+	version := graph.Version()
+
+	path, err := graph.Dijkstra(0, algo.WithForbiddenEdges(bridge))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cost := path.PathCost(3); cost != 10 {
+		t.Error("Closed bridge used", cost)
+	}
+	path, _ = graph.Dijkstra(3, algo.WithForbiddenEdges(bridge))
+	if cost := path.PathCost(1); cost != 11 {
+		t.Error("Closed bridge used backwards", cost)
+	}
+
+	path, _ = graph.Dijkstra(0)
+	if cost := path.PathCost(3); cost != 3 {
+		t.Error("Bridge still closed", cost)
+	}
+	if graph.Version() != version {
+		t.Error("Graph changed")
+	}
+}
+
+func TestForbiddenVertexes(t *testing.T) {
+	graph, _ := bridgeGraph()
+	path, _ := graph.Dijkstra(0, algo.WithForbiddenVertexes(2, 4))
+	if cost := path.PathCost(3); cost != algo.UndefDist {
+		t.Error("Forbidden vertex crossed", cost)
+	}
+	if cost := path.PathCost(2); cost != algo.UndefDist {
+		t.Error("Forbidden vertex reached", cost)
+	}
+
+	path, _ = graph.Dijkstra(2, algo.WithForbiddenVertexes(2))
+	if cost := path.PathCost(2); cost != 0 {
+		t.Error("Forbidden source not settled", cost)
+	}
+	if cost := path.PathCost(1); cost != algo.UndefDist {
+		t.Error("Forbidden source left", cost)
+	}
+
+	route, cost, err := algo.AStar(graph, 0, 3, func(int) int { return 0 }, algo.WithForbiddenVertexes(1))
+	if err != nil || cost != 10 || len(route) != 3 {
+		t.Error("AStar ignored the restriction", route, cost, err)
+	}
+	bfs, _ := algo.BFS(graph, 0, algo.WithForbiddenVertexes(4))
+	if hops := bfs.PathCost(3); hops != 3 {
+		t.Error("BFS ignored the restriction", hops)
+	}
+}
+
+func TestCostOverrides(t *testing.T) {
+	graph, bridge := bridgeGraph()
+	path, _ := graph.Dijkstra(0, algo.WithCostOverrides(map[algo.EdgeID]int{bridge: 20}))
+	if cost := path.PathCost(3); cost != 10 {
+		t.Error("Override ignored", cost)
+	}
+	if cost, _ := graph.EdgeCost(1, 2); cost != 1 {
+		t.Error("Graph changed", cost)
+	}
+
+	path, _ = graph.Dijkstra(0, algo.WithCostMultipliers(map[algo.EdgeID]float64{bridge: 7.6}))
+	if cost := path.PathCost(3); cost != 10 {
+		t.Error("Multiplier ignored", cost)
+	}
+	path, _ = graph.Dijkstra(0,
+		algo.WithCostOverrides(map[algo.EdgeID]int{bridge: 2}),
+		algo.WithCostMultipliers(map[algo.EdgeID]float64{bridge: 3.4}),
+	)
+	if cost := path.PathCost(2); cost != 8 {
+		t.Error("Override not multiplied", cost)
+	}
+
+	// costs past the Dial queue bound must not be bucketed out of range
+	path, _ = graph.Dijkstra(0,
+		algo.WithCostOverrides(map[algo.EdgeID]int{bridge: 1 << 20}),
+		algo.WithForbiddenVertexes(4),
+	)
+	if cost := path.PathCost(2); cost != 1<<20+1 {
+		t.Error("Wrong cost of a large override", cost)
+	}
+
+	for _, factor := range []float64{-1, math.NaN(), math.Inf(1)} {
+		multiplier := algo.WithCostMultipliers(map[algo.EdgeID]float64{bridge: factor})
+		if _, err := graph.Dijkstra(0, multiplier); err != algo.ErrCostFactor {
+			t.Error("Bad factor accepted by Dijkstra", factor, err)
+		}
+		if _, _, err := algo.AStar(graph, 0, 3, func(int) int { return 0 }, multiplier); err != algo.ErrCostFactor {
+			t.Error("Bad factor accepted by A*", factor, err)
+		}
+	}
+}
+
+func TestRestrictionsConcurrent(t *testing.T) {
+	graph, bridge := bridgeGraph()
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(closed bool) {
+			defer wg.Done()
+			opts := []algo.Option{}
+			want := 3
+			if closed {
+				opts = append(opts, algo.WithForbiddenEdges(bridge))
+				want = 10
+			}
+			for j := 0; j < 100; j++ {
+				path, _ := graph.Dijkstra(0, opts...)
+				if cost := path.PathCost(3); cost != want {
+					t.Error("Restrictions leaked between queries", cost)
+					return
+				}
+			}
+		}(i%2 == 0)
+	}
+	wg.Wait()
+}
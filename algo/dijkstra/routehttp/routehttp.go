@@ -0,0 +1,310 @@
+// Package routehttp serves shortest path queries and graph updates over
+// HTTP with JSON bodies.
+//
+//	POST   /route   {"source": 0, "target": 5}       -> {"cost": 7, "path": [0, 2, 5]}
+//	POST   /matrix  {"sources": [0], "targets": [5]} -> {"costs": [[7]]}
+//	POST   /edges   {"from": 0, "to": 5, "cost": 7}  -> {"id": 12}
+//	DELETE /edges?id=12  or  /edges?from=0&to=5      -> {"deleted": 1}
+//
+// Failures are answered with {"error": "..."} and a 4xx status for bad
+// requests, 503 when the request is cancelled during a search, which then
+// stops, and 500 when the search fails otherwise.
+package routehttp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	algo "github.com/octo47/gomisc/algo/dijkstra"
+)
+
+const (
+	// MaxBodyBytes limits the size of a request body.
+	MaxBodyBytes = 1 << 20
+	// MaxMatrixCells limits sources times targets of a distance matrix.
+	MaxMatrixCells = 1 << 16
+	// MaxMatrixSources limits the sources of a distance matrix, each is a
+	// search holding up edge updates.
+	MaxMatrixSources = 1 << 6
+)
+
+var errNoPath = errors.New("No path between vertexes")
+
+// Handler is an http.Handler over a graph. Queries run concurrently with
+// each other, edge updates run alone.
+type Handler struct {
+	mu    sync.RWMutex
+	graph *algo.Graph
+	mux   *http.ServeMux
+}
+
+// NewHandler returns a handler serving the graph. The graph must not be
+// touched afterwards other than through View and Update.
+func NewHandler(g *algo.Graph) *Handler {
+	h := &Handler{graph: g, mux: http.NewServeMux()}
+	h.mux.HandleFunc("/route", h.route)
+	h.mux.HandleFunc("/matrix", h.matrix)
+	h.mux.HandleFunc("/edges", h.edges)
+	return h
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+// View calls fn with the graph locked for reading.
+func (h *Handler) View(fn func(g *algo.Graph)) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	fn(h.graph)
+}
+
+// Update calls fn with the graph locked for writing.
+func (h *Handler) Update(fn func(g *algo.Graph)) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	fn(h.graph)
+}
+
+// RouteRequest asks for the shortest path between two vertexes.
+type RouteRequest struct {
+	Source int `json:"source"`
+	Target int `json:"target"`
+}
+
+// RouteResponse is a shortest path from source to target.
+type RouteResponse struct {
+	Cost int   `json:"cost"`
+	Path []int `json:"path"`
+}
+
+// MatrixRequest asks for the path costs from every source to every target.
+type MatrixRequest struct {
+	Sources []int `json:"sources"`
+	Targets []int `json:"targets"`
+}
+
+// MatrixResponse holds Costs[i][j] from the i-th source to the j-th
+// target, null where the target can't be reached.
+type MatrixResponse struct {
+	Costs [][]*int `json:"costs"`
+}
+
+// EdgeRequest adds an edge.
+type EdgeRequest struct {
+	From  int  `json:"from"`
+	To    int  `json:"to"`
+	Cost  int  `json:"cost"`
+	Bidir bool `json:"bidir,omitempty"`
+}
+
+// EdgeResponse identifies the added edge.
+type EdgeResponse struct {
+	ID algo.EdgeID `json:"id"`
+}
+
+// DeleteResponse tells how many edges were removed.
+type DeleteResponse struct {
+	Deleted int `json:"deleted"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func (h *Handler) route(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodPost) {
+		return
+	}
+	var req RouteRequest
+	if !decode(w, r, &req) {
+		return
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	if !checkVertexes(w, h.graph, req.Source, req.Target) {
+		return
+	}
+	path, err := algo.DijkstraContext(r.Context(), h.graph, req.Source)
+	if err != nil {
+		searchFailed(w, err)
+		return
+	}
+	cost := path.PathCost(req.Target)
+	if cost == algo.UndefDist {
+		fail(w, http.StatusNotFound, errNoPath)
+		return
+	}
+	vertexes := path.BuildPath(req.Target)
+	for i, j := 0, len(vertexes)-1; i < j; i, j = i+1, j-1 {
+		vertexes[i], vertexes[j] = vertexes[j], vertexes[i]
+	}
+	reply(w, http.StatusOK, RouteResponse{Cost: cost, Path: vertexes})
+}
+
+func (h *Handler) matrix(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodPost) {
+		return
+	}
+	var req MatrixRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	if len(req.Sources) > MaxMatrixSources {
+		fail(w, http.StatusRequestEntityTooLarge,
+			fmt.Errorf("Matrix has more than %d sources", MaxMatrixSources))
+		return
+	}
+	if len(req.Sources) > 0 && len(req.Targets) > MaxMatrixCells/len(req.Sources) {
+		fail(w, http.StatusRequestEntityTooLarge,
+			fmt.Errorf("Matrix is larger than %d cells", MaxMatrixCells))
+		return
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	if !checkVertexes(w, h.graph, req.Sources...) || !checkVertexes(w, h.graph, req.Targets...) {
+		return
+	}
+	costs := make([][]*int, len(req.Sources))
+	for i, source := range req.Sources {
+		path, err := algo.DijkstraContext(r.Context(), h.graph, source)
+		if err != nil {
+			searchFailed(w, err)
+			return
+		}
+		costs[i] = make([]*int, len(req.Targets))
+		for j, target := range req.Targets {
+			if cost := path.PathCost(target); cost != algo.UndefDist {
+				costs[i][j] = &cost
+			}
+		}
+	}
+	reply(w, http.StatusOK, MatrixResponse{Costs: costs})
+}
+
+func (h *Handler) edges(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodPost, http.MethodDelete) {
+		return
+	}
+	if r.Method == http.MethodDelete {
+		h.deleteEdges(w, r)
+		return
+	}
+	var req EdgeRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	if req.Cost < 0 {
+		fail(w, http.StatusBadRequest, algo.ErrNegativeCost)
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if !checkVertexes(w, h.graph, req.From, req.To) {
+		return
+	}
+	id := h.graph.AddEdge(req.From, req.To, req.Cost, req.Bidir)
+	reply(w, http.StatusCreated, EdgeResponse{ID: id})
+}
+
+func (h *Handler) deleteEdges(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	params := make(map[string]int)
+	for _, name := range []string{"id", "from", "to"} {
+		if s := query.Get(name); s != "" {
+			v, err := strconv.Atoi(s)
+			if err != nil {
+				fail(w, http.StatusBadRequest, fmt.Errorf("Bad %s %q", name, s))
+				return
+			}
+			params[name] = v
+		}
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	id, byID := params["id"]
+	from, hasFrom := params["from"]
+	to, hasTo := params["to"]
+	switch {
+	case byID && !hasFrom && !hasTo:
+		if err := h.graph.DelEdgeByID(algo.EdgeID(id)); err != nil {
+			fail(w, http.StatusNotFound, err)
+			return
+		}
+		reply(w, http.StatusOK, DeleteResponse{Deleted: 1})
+	case !byID && hasFrom && hasTo:
+		if !checkVertexes(w, h.graph, from, to) {
+			return
+		}
+		reply(w, http.StatusOK, DeleteResponse{Deleted: h.graph.DelAllEdges(from, to)})
+	default:
+		fail(w, http.StatusBadRequest, errors.New("Need either id or from and to"))
+	}
+}
+
+// allowMethods answers 405 unless the request uses one of the methods
+func allowMethods(w http.ResponseWriter, r *http.Request, methods ...string) bool {
+	for _, m := range methods {
+		if r.Method == m {
+			return true
+		}
+	}
+	w.Header().Set("Allow", strings.Join(methods, ", "))
+	fail(w, http.StatusMethodNotAllowed, fmt.Errorf("Method %s not allowed", r.Method))
+	return false
+}
+
+// decode reads a single JSON value into v, answering 400 if it can't
+func decode(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, MaxBodyBytes))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		fail(w, http.StatusBadRequest, fmt.Errorf("Bad request body: %v", err))
+		return false
+	}
+	if dec.More() {
+		fail(w, http.StatusBadRequest, errors.New("Bad request body: trailing data"))
+		return false
+	}
+	return true
+}
+
+// checkVertexes answers 400 unless every vertex is in the graph
+func checkVertexes(w http.ResponseWriter, g *algo.Graph, vertexes ...int) bool {
+	for _, v := range vertexes {
+		if v < 0 || v >= g.VertexCount() {
+			fail(w, http.StatusBadRequest, fmt.Errorf("%v: %d", algo.ErrVertexRange, v))
+			return false
+		}
+	}
+	return true
+}
+
+// searchFailed answers 503 for a cancelled request and 500 otherwise
+func searchFailed(w http.ResponseWriter, err error) {
+	if err == context.Canceled || err == context.DeadlineExceeded {
+		fail(w, http.StatusServiceUnavailable, err)
+		return
+	}
+	fail(w, http.StatusInternalServerError, err)
+}
+
+func fail(w http.ResponseWriter, status int, err error) {
+	reply(w, status, errorResponse{Error: err.Error()})
+}
+
+func reply(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,197 @@
+package routehttp_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	algo "github.com/octo47/gomisc/algo/dijkstra"
+	"github.com/octo47/gomisc/algo/dijkstra/routehttp"
+)
+
+func newServer() *httptest.Server {
+	g := algo.NewGraph()
+	g.AddVertexes(4)
+	g.AddEdge(0, 1, 2, false)
+	g.AddEdge(1, 2, 2, false)
+	g.AddEdge(0, 2, 5, false)
+	return httptest.NewServer(routehttp.NewHandler(g))
+}
+
+func call(t *testing.T, srv *httptest.Server, method, path, body string, out interface{}) int {
+	t.Helper()
+	req, err := http.NewRequest(method, srv.URL+path, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := srv.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "application/json" {
+		t.Error("Wrong content type", ct)
+	}
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return resp.StatusCode
+}
+
+func TestRoute(t *testing.T) {
+	srv := newServer()
+	defer srv.Close()
+
+	var route routehttp.RouteResponse
+	if status := call(t, srv, "POST", "/route", `{"source": 0, "target": 2}`, &route); status != http.StatusOK {
+		t.Fatal("Wrong status", status)
+	}
+	want := routehttp.RouteResponse{Cost: 4, Path: []int{0, 1, 2}}
+	if !reflect.DeepEqual(route, want) {
+		t.Error("Wrong route", route)
+	}
+
+	for _, tc := range []struct {
+		method, body string
+		status       int
+	}{
+		{"POST", `{"source": 0, "target": 3}`, http.StatusNotFound},
+		{"POST", `{"source": 0, "target": 4}`, http.StatusBadRequest},
+		{"POST", `{"source": 0, "dest": 2}`, http.StatusBadRequest},
+		{"POST", `{"source": 0`, http.StatusBadRequest},
+		{"POST", `{"source": 0, "target": 2} {}`, http.StatusBadRequest},
+		{"GET", ``, http.StatusMethodNotAllowed},
+	} {
+		var e struct{ Error string }
+		if status := call(t, srv, tc.method, "/route", tc.body, &e); status != tc.status {
+			t.Error(tc.body, "wrong status", status)
+		}
+		if e.Error == "" {
+			t.Error(tc.body, "no error message")
+		}
+	}
+}
+
+func TestMatrix(t *testing.T) {
+	srv := newServer()
+	defer srv.Close()
+
+	var matrix routehttp.MatrixResponse
+	status := call(t, srv, "POST", "/matrix", `{"sources": [0, 2], "targets": [1, 2, 3]}`, &matrix)
+	if status != http.StatusOK {
+		t.Fatal("Wrong status", status)
+	}
+	got, err := json.Marshal(matrix.Costs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "[[2,4,null],[null,0,null]]" {
+		t.Error("Wrong matrix", string(got))
+	}
+
+	big := `{"sources": [` + strings.Repeat("0,", 60) + `0], "targets": [` + strings.Repeat("0,", 3000) + `0]}`
+	if status := call(t, srv, "POST", "/matrix", big, nil); status != http.StatusRequestEntityTooLarge {
+		t.Error("Huge matrix accepted", status)
+	}
+	many := `{"sources": [` + strings.Repeat("0,", routehttp.MaxMatrixSources) + `0], "targets": [0]}`
+	if status := call(t, srv, "POST", "/matrix", many, nil); status != http.StatusRequestEntityTooLarge {
+		t.Error("Too many sources accepted", status)
+	}
+}
+
+func TestCancelledRequest(t *testing.T) {
+	g := algo.NewGraph()
+	g.AddVertexes(2)
+	g.AddEdge(0, 1, 1, false)
+	handler := routehttp.NewHandler(g)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for path, body := range map[string]string{
+		"/route":  `{"source": 0, "target": 1}`,
+		"/matrix": `{"sources": [0], "targets": [1]}`,
+	} {
+		req := httptest.NewRequest("POST", path, strings.NewReader(body)).WithContext(ctx)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != http.StatusServiceUnavailable {
+			t.Error(path, "must stop searching", rec.Code)
+		}
+	}
+}
+
+func TestEdges(t *testing.T) {
+	srv := newServer()
+	defer srv.Close()
+
+	var added routehttp.EdgeResponse
+	if status := call(t, srv, "POST", "/edges", `{"from": 2, "to": 3, "cost": 1, "bidir": true}`, &added); status != http.StatusCreated {
+		t.Fatal("Wrong status", status)
+	}
+	var route routehttp.RouteResponse
+	call(t, srv, "POST", "/route", `{"source": 3, "target": 2}`, &route)
+	if route.Cost != 1 {
+		t.Error("Added edge not used", route)
+	}
+
+	if status := call(t, srv, "POST", "/edges", `{"from": 2, "to": 3, "cost": -1}`, nil); status != http.StatusBadRequest {
+		t.Error("Negative cost accepted", status)
+	}
+
+	var deleted routehttp.DeleteResponse
+	if status := call(t, srv, "DELETE", "/edges?from=0&to=1", "", &deleted); status != http.StatusOK || deleted.Deleted != 1 {
+		t.Error("Delete by ends failed", status, deleted)
+	}
+	call(t, srv, "POST", "/route", `{"source": 0, "target": 2}`, &route)
+	if route.Cost != 5 {
+		t.Error("Deleted edge still used", route)
+	}
+
+	path := "/edges?id=" + strconv.Itoa(int(added.ID))
+	if status := call(t, srv, "DELETE", path, "", &deleted); status != http.StatusOK {
+		t.Error("Delete by id failed", status)
+	}
+	if status := call(t, srv, "DELETE", path, "", nil); status != http.StatusNotFound {
+		t.Error("Deleted twice", status)
+	}
+	for _, bad := range []string{"/edges", "/edges?id=x", "/edges?id=1&from=0&to=1", "/edges?from=0&to=9"} {
+		if status := call(t, srv, "DELETE", bad, "", nil); status != http.StatusBadRequest {
+			t.Error(bad, "wrong status", status)
+		}
+	}
+}
+
+func TestConcurrentAccess(t *testing.T) {
+	srv := newServer()
+	defer srv.Close()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				if i%2 == 0 {
+					call(t, srv, "POST", "/edges", `{"from": 3, "to": 0, "cost": 1}`, nil)
+				} else {
+					call(t, srv, "POST", "/route", `{"source": 0, "target": 2}`, nil)
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	var route routehttp.RouteResponse
+	call(t, srv, "POST", "/route", `{"source": 3, "target": 2}`, &route)
+	if route.Cost != 5 {
+		t.Error("Wrong route after updates", route)
+	}
+}
@@ -0,0 +1,136 @@
+package dijkstra
+
+// StronglyConnectedComponents labels every vertex with the id of its
+// strongly connected component, see the package function.
+func (g *Graph) StronglyConnectedComponents() ([]int, int) {
+	return StronglyConnectedComponents(g)
+}
+
+// Condensation builds the DAG of strongly connected components, see the
+// package function.
+func (g *Graph) Condensation() (*Graph, []int) {
+	dag, comp := Condensation(g)
+	return dag.graph, comp
+}
+
+// StronglyConnectedComponents labels every vertex with the id of its
+// strongly connected component and returns the labels together with the
+// number of components. Components are numbered in topological order of the
+// condensation: an edge between two different components always goes from
+// a lower id to a higher one.
+//
+// Tarjan's algorithm is run with an explicit call stack, so deep graphs
+// don't overflow the goroutine stack.
+func StronglyConnectedComponents(g Interface) ([]int, int) {
+	n := g.VertexCount()
+	index := make([]int, n) // 0 means not visited yet
+	low := make([]int, n)
+	onStack := make([]bool, n)
+	comp := make([]int, n)
+	for i := 0; i < n; i++ {
+		comp[i] = Undef
+	}
+
+	// neighbors of the vertexes on the call stack, stacked the same way:
+	// the frame of a vertex spans from first[v] to the next frame
+	targets := make([]int, 0)
+	first := make([]int, n)
+	next := make([]int, n)
+
+	stack := NewStack()
+	call := NewStack()
+	counter := 0
+	count := 0
+	visit := func(v int) {
+		counter++
+		index[v] = counter
+		low[v] = counter
+		stack.Push(v)
+		onStack[v] = true
+		call.Push(v)
+		first[v] = len(targets)
+		next[v] = len(targets)
+		g.Neighbors(v, func(e Edge) bool {
+			targets = append(targets, e.To)
+			return true
+		})
+	}
+
+	for root := 0; root < n; root++ {
+		if index[root] != 0 {
+			continue
+		}
+		visit(root)
+		for call.Len() > 0 {
+			u, _ := call.Peek()
+			// u is on top, so its frame runs to the end of targets
+			if next[u] < len(targets) {
+				v := targets[next[u]]
+				next[u]++
+				if index[v] == 0 {
+					visit(v)
+				} else if onStack[v] && index[v] < low[u] {
+					low[u] = index[v]
+				}
+				continue
+			}
+			call.Pop()
+			targets = targets[:first[u]]
+			if p, err := call.Peek(); err == nil && low[u] < low[p] {
+				low[p] = low[u]
+			}
+			if low[u] == index[u] {
+				for {
+					w, _ := stack.Pop()
+					onStack[w] = false
+					comp[w] = count
+					if w == u {
+						break
+					}
+				}
+				count++
+			}
+		}
+	}
+
+	// Tarjan emits components in reverse topological order
+	for i := 0; i < n; i++ {
+		comp[i] = count - 1 - comp[i]
+	}
+	return comp, count
+}
+
+// Condensation builds the DAG of strongly connected components. Vertex i of
+// the returned graph is component i as labelled by StronglyConnectedComponents,
+// and the returned slice maps every vertex of g to its component. Parallel
+// edges between two components are merged into one edge with the minimal cost.
+func Condensation(g Interface) (*DirectedGraph, []int) {
+	comp, count := StronglyConnectedComponents(g)
+	dag := NewDirectedGraph()
+	dag.AddVertexes(count)
+
+	type link struct {
+		from, to int
+	}
+	costs := make(map[link]int)
+	order := make([]link, 0)
+	for u := 0; u < g.VertexCount(); u++ {
+		g.Neighbors(u, func(e Edge) bool {
+			l := link{from: comp[u], to: comp[e.To]}
+			if l.from == l.to {
+				return true
+			}
+			if c, ok := costs[l]; !ok {
+				costs[l] = e.Cost
+				order = append(order, l)
+			} else if e.Cost < c {
+				costs[l] = e.Cost
+			}
+			return true
+		})
+	}
+	for _, l := range order {
+		dag.AddEdge(l.from, l.to, costs[l])
+	}
+	return dag, comp
+}
@@ -0,0 +1,58 @@
+package dijkstra_test
+
+import (
+	"testing"
+
+	algo "github.com/octo47/gomisc/algo/dijkstra"
+)
+
+func TestStronglyConnectedComponents(t *testing.T) {
+	graph := algo.NewGraph()
+	graph.AddVertexes(6)
+	// {0,1,2} -> {3,4} -> {5}
+	graph.AddEdge(0, 1, 1, false)
+	graph.AddEdge(1, 2, 1, false)
+	graph.AddEdge(2, 0, 1, false)
+	graph.AddEdge(2, 3, 5, false)
+	graph.AddEdge(1, 4, 2, false)
+	graph.AddEdge(3, 4, 1, true)
+	graph.AddEdge(4, 5, 1, false)
+
+	comp, count := graph.StronglyConnectedComponents()
+	if count != 3 {
+		t.Fatal("Wrong component count", count, comp)
+	}
+	if comp[0] != comp[1] || comp[1] != comp[2] || comp[3] != comp[4] {
+		t.Fatal("Wrong components", comp)
+	}
+	if !(comp[0] < comp[3] && comp[3] < comp[5]) {
+		t.Error("Components are not in topological order", comp)
+	}
+
+	dag, mapping := graph.Condensation()
+	path, err := dag.Dijkstra(mapping[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cost := path.PathCost(mapping[5]); cost != 3 {
+		t.Error("Wrong condensation costs", cost)
+	}
+	if _, dagCount := dag.StronglyConnectedComponents(); dagCount != 3 {
+		t.Error("Condensation is not a DAG", dagCount)
+	}
+}
+
+func TestStronglyConnectedComponentsDeepChain(t *testing.T) {
+	const n = 1000000
+	graph := algo.NewGraph()
+	graph.AddVertexes(n)
+	for i := 0; i+1 < n; i++ {
+		graph.AddEdge(i, i+1, 1, false)
+	}
+	graph.AddEdge(n-1, 0, 1, false)
+
+	comp, count := graph.StronglyConnectedComponents()
+	if count != 1 || comp[0] != 0 || comp[n-1] != 0 {
+		t.Error("Cycle must be a single component", count)
+	}
+}
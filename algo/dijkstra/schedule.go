@@ -0,0 +1,246 @@
+package dijkstra
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Task is an edge of a task graph seen as a task taking Duration to go
+// from the event of its From vertex to the one of its To vertex.
+type Task struct {
+	ID            EdgeID
+	From          int
+	To            int
+	Duration      int
+	EarliestStart int
+	LatestStart   int
+	// Slack is how long the task can be delayed without delaying the
+	// project, zero for critical tasks.
+	Slack int
+}
+
+func (t Task) EarliestFinish() int {
+	return t.EarliestStart + t.Duration
+}
+
+func (t Task) Critical() bool {
+	return t.Slack == 0
+}
+
+// Schedule is the critical path analysis of a task graph.
+type Schedule struct {
+	// Duration is the length of the whole project.
+	Duration int
+	// Tasks in the order of the vertexes they start from.
+	Tasks []Task
+	// Earliest and latest time of the event of every vertex.
+	Earliest []int
+	Latest   []int
+}
+
+// CycleError reports a cycle of a task graph, which can't be scheduled.
+// Edges[i] goes from Vertexes[i] to Vertexes[i+1], the last one back to
+// Vertexes[0].
+type CycleError struct {
+	Vertexes []int
+	Edges    []EdgeID
+}
+
+func (e *CycleError) Error() string {
+	parts := make([]string, 0, len(e.Vertexes)+1)
+	for _, v := range e.Vertexes {
+		parts = append(parts, strconv.Itoa(v))
+	}
+	parts = append(parts, strconv.Itoa(e.Vertexes[0]))
+	return fmt.Sprintf("task graph has a cycle: %s (edges %v)", strings.Join(parts, " -> "), e.Edges)
+}
+
+// CriticalPath schedules the task graph, see the package function.
+func (g *Graph) CriticalPath() (*Schedule, error) {
+	return CriticalPath(g)
+}
+
+// CriticalPath runs the critical path method on g, a DAG whose edges are
+// tasks lasting their cost and whose vertexes are the events of tasks
+// finishing and starting: every task leaving a vertex may start once all
+// the tasks entering it are done. A cyclic graph is rejected with a
+// *CycleError naming one of the cycles, a negative duration with
+// ErrNegativeCost.
+func CriticalPath(g Interface) (*Schedule, error) {
+	n := g.VertexCount()
+	tasks := make([]Task, 0)
+	out := make([][]int, n)
+	indegree := make([]int, n)
+	for u := 0; u < n; u++ {
+		g.Neighbors(u, func(e Edge) bool {
+			out[u] = append(out[u], len(tasks))
+			tasks = append(tasks, Task{ID: e.ID, From: e.From, To: e.To, Duration: e.Cost})
+			indegree[e.To]++
+			return true
+		})
+	}
+	for _, t := range tasks {
+		if t.Duration < 0 {
+			return nil, ErrNegativeCost
+		}
+	}
+
+	// Kahn's algorithm, the event times follow in topological order
+	order := make([]int, 0, n)
+	for v := 0; v < n; v++ {
+		if indegree[v] == 0 {
+			order = append(order, v)
+		}
+	}
+	for i := 0; i < len(order); i++ {
+		for _, t := range out[order[i]] {
+			v := tasks[t].To
+			indegree[v]--
+			if indegree[v] == 0 {
+				order = append(order, v)
+			}
+		}
+	}
+	if len(order) < n {
+		return nil, findCycle(tasks, indegree)
+	}
+
+	s := &Schedule{Tasks: tasks, Earliest: make([]int, n), Latest: make([]int, n)}
+	for _, u := range order {
+		for _, t := range out[u] {
+			if finish := s.Earliest[u] + tasks[t].Duration; finish > s.Earliest[tasks[t].To] {
+				s.Earliest[tasks[t].To] = finish
+			}
+		}
+		if s.Earliest[u] > s.Duration {
+			s.Duration = s.Earliest[u]
+		}
+	}
+	for i := len(order) - 1; i >= 0; i-- {
+		u := order[i]
+		s.Latest[u] = s.Duration
+		for _, t := range out[u] {
+			if start := s.Latest[tasks[t].To] - tasks[t].Duration; start < s.Latest[u] {
+				s.Latest[u] = start
+			}
+		}
+	}
+	for i := range tasks {
+		t := &tasks[i]
+		t.EarliestStart = s.Earliest[t.From]
+		t.LatestStart = s.Latest[t.To] - t.Duration
+		t.Slack = t.LatestStart - t.EarliestStart
+	}
+	return s, nil
+}
+
+// findCycle walks back along the tasks between the vertexes Kahn's
+// algorithm couldn't order: each of them has such a task entering it, so
+// the walk runs into a cycle
+func findCycle(tasks []Task, indegree []int) *CycleError {
+	n := len(indegree)
+	in := make([]int, n)
+	for i := range in {
+		in[i] = Undef
+	}
+	start := Undef
+	for i, t := range tasks {
+		if indegree[t.From] > 0 && indegree[t.To] > 0 {
+			in[t.To] = i
+			start = t.To
+		}
+	}
+
+	step := make([]int, n)
+	for i := range step {
+		step[i] = Undef
+	}
+	walk := make([]int, 0)
+	v := start
+	for step[v] == Undef {
+		step[v] = len(walk)
+		walk = append(walk, in[v])
+		v = tasks[in[v]].From
+	}
+	// the walk went backwards, the cycle is the tail of it reversed
+	loop := walk[step[v]:]
+	e := &CycleError{Vertexes: make([]int, len(loop)), Edges: make([]EdgeID, len(loop))}
+	for i := range loop {
+		t := tasks[loop[len(loop)-1-i]]
+		e.Vertexes[i] = t.From
+		e.Edges[i] = t.ID
+	}
+	return e
+}
+
+// Critical returns a critical path: a chain of critical tasks from the
+// start of the project to its end.
+func (s *Schedule) Critical() []Task {
+	path := make([]Task, 0)
+	next := make(map[int]Task)
+	v := Undef
+	for _, t := range s.Tasks {
+		if !t.Critical() {
+			continue
+		}
+		if _, ok := next[t.From]; !ok {
+			next[t.From] = t
+		}
+		if v == Undef && t.EarliestStart == 0 {
+			v = t.From
+		}
+	}
+	// a critical task always leaves the end of a critical task before
+	// the project ends, and the graph has no cycles
+	for t, ok := next[v]; ok; t, ok = next[t.To] {
+		path = append(path, t)
+	}
+	return path
+}
+
+// Gantt renders the schedule as text, a line per task ordered by earliest
+// start, with a bar of the given width spanning the project: '=' marks a
+// critical task, '#' a task at its earliest and '.' the slack after it.
+func (s *Schedule) Gantt(width int) string {
+	if width < 1 {
+		width = 1
+	}
+	tasks := make([]Task, len(s.Tasks))
+	copy(tasks, s.Tasks)
+	sort.SliceStable(tasks, func(i, j int) bool {
+		return tasks[i].EarliestStart < tasks[j].EarliestStart
+	})
+	column := func(t int) int {
+		if s.Duration == 0 {
+			return 0
+		}
+		return t * width / s.Duration
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%-14s %6s %6s %6s\n", "task", "start", "finish", "slack")
+	for _, t := range tasks {
+		bar := []byte(strings.Repeat(" ", width))
+		mark := byte('#')
+		if t.Critical() {
+			mark = '='
+		}
+		start, finish, latest := column(t.EarliestStart), column(t.EarliestFinish()), column(t.LatestStart+t.Duration)
+		if finish == start && t.Duration > 0 && start < width {
+			finish = start + 1
+		}
+		for i := start; i < finish && i < width; i++ {
+			bar[i] = mark
+		}
+		for i := finish; i < latest && i < width; i++ {
+			bar[i] = '.'
+		}
+		label := fmt.Sprintf("%d (%d->%d)", t.ID, t.From, t.To)
+		fmt.Fprintf(&buf, "%-14s %6d %6d %6d |%s|\n", label, t.EarliestStart, t.EarliestFinish(), t.Slack, bar)
+	}
+	fmt.Fprintf(&buf, "duration %d\n", s.Duration)
+	return buf.String()
+}
@@ -0,0 +1,118 @@
+package dijkstra_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	algo "github.com/octo47/gomisc/algo/dijkstra"
+)
+
+// two critical paths: 0-1-3-5 and 0-2-4-5, both taking 9
+func taskGraph() *algo.Graph {
+	graph := algo.NewGraph()
+	graph.AddVertexes(6)
+	graph.AddEdge(0, 1, 3, false)
+	graph.AddEdge(0, 2, 2, false)
+	graph.AddEdge(1, 3, 4, false)
+	graph.AddEdge(2, 3, 1, false)
+	graph.AddEdge(2, 4, 6, false)
+	graph.AddEdge(3, 5, 2, false)
+	graph.AddEdge(4, 5, 1, false)
+	return graph
+}
+
+func TestCriticalPath(t *testing.T) {
+	s, err := taskGraph().CriticalPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Duration != 9 {
+		t.Error("Wrong duration", s.Duration)
+	}
+	if !reflect.DeepEqual(s.Earliest, []int{0, 3, 2, 7, 8, 9}) {
+		t.Error("Wrong earliest times", s.Earliest)
+	}
+	if !reflect.DeepEqual(s.Latest, []int{0, 3, 2, 7, 8, 9}) {
+		t.Error("Wrong latest times", s.Latest)
+	}
+	slack := make([]int, len(s.Tasks))
+	for _, task := range s.Tasks {
+		slack[task.ID] = task.Slack
+	}
+	if !reflect.DeepEqual(slack, []int{0, 0, 0, 4, 0, 0, 0}) {
+		t.Error("Wrong slack", slack)
+	}
+	d := s.Tasks[3]
+	if d.ID != 3 || d.EarliestStart != 2 || d.LatestStart != 6 || d.EarliestFinish() != 3 || d.Critical() {
+		t.Error("Wrong task", d)
+	}
+
+	ids := make([]algo.EdgeID, 0)
+	for _, task := range s.Critical() {
+		ids = append(ids, task.ID)
+	}
+	if !reflect.DeepEqual(ids, []algo.EdgeID{0, 2, 5}) {
+		t.Error("Wrong critical path", ids)
+	}
+}
+
+func TestCriticalPathCycle(t *testing.T) {
+	graph := algo.NewGraph()
+	graph.AddVertexes(5)
+	graph.AddEdge(0, 1, 1, false)
+	graph.AddEdge(1, 2, 1, false)
+	graph.AddEdge(2, 3, 1, false)
+	graph.AddEdge(3, 1, 1, false)
+	graph.AddEdge(3, 4, 1, false)
+
+	_, err := graph.CriticalPath()
+	cycle, ok := err.(*algo.CycleError)
+	if !ok {
+		t.Fatal("Cycle not reported", err)
+	}
+	if len(cycle.Vertexes) != 3 || len(cycle.Edges) != 3 {
+		t.Fatal("Wrong cycle", cycle)
+	}
+	ends := map[algo.EdgeID][2]int{1: {1, 2}, 2: {2, 3}, 3: {3, 1}}
+	for i, id := range cycle.Edges {
+		want := [2]int{cycle.Vertexes[i], cycle.Vertexes[(i+1)%3]}
+		if ends[id] != want {
+			t.Error("Edge", id, "doesn't go", want)
+		}
+	}
+	if !strings.Contains(err.Error(), "->") {
+		t.Error("Cycle not spelled out", err)
+	}
+
+	loop := algo.NewGraph()
+	loop.AddVertexes(1)
+	loop.AddEdge(0, 0, 1, false)
+	if _, err := loop.CriticalPath(); err == nil || err.Error() != "task graph has a cycle: 0 -> 0 (edges [0])" {
+		t.Error("Wrong self-loop report", err)
+	}
+
+	negative := algo.NewGraph()
+	negative.AddVertexes(2)
+	negative.AddEdge(0, 1, -1, false)
+	if _, err := negative.CriticalPath(); err != algo.ErrNegativeCost {
+		t.Error("Negative duration accepted", err)
+	}
+}
+
+func TestGantt(t *testing.T) {
+	s, _ := taskGraph().CriticalPath()
+	want := `task            start finish  slack
+0 (0->1)            0      3      0 |===      |
+1 (0->2)            0      2      0 |==       |
+3 (2->3)            2      3      4 |  #....  |
+4 (2->4)            2      8      0 |  ====== |
+2 (1->3)            3      7      0 |   ====  |
+5 (3->5)            7      9      0 |       ==|
+6 (4->5)            8      9      0 |        =|
+duration 9
+`
+	if got := s.Gantt(9); got != want {
+		t.Errorf("Wrong chart\n%s", got)
+	}
+}
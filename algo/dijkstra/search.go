@@ -0,0 +1,181 @@
+package dijkstra
+
+import (
+	"context"
+	"errors"
+)
+
+var ErrNoPath = errors.New("Target is unreachable")
+
+// BFS finds the paths with the fewest edges from source to every vertex,
+// ignoring costs. PathCost of the result is the number of edges.
+func BFS(g Interface, source int, opts ...Option) (*Path, error) {
+	return BFSContext(context.Background(), g, source, opts...)
+}
+
+// BFSContext is BFS which gives up with ctx.Err() once the context is done.
+func BFSContext(ctx context.Context, g Interface, source int, opts ...Option) (*Path, error) {
+	n := g.VertexCount()
+	if source < 0 || source >= n {
+		return nil, ErrVertexRange
+	}
+	o := newOptions(opts)
+	g, err := o.view(g)
+	if err != nil {
+		return nil, err
+	}
+	m, err := newMonitor(ctx, o)
+	if err != nil {
+		return nil, err
+	}
+	visitor := o.visitor
+	dist := make([]int, n)
+	prev := make([]int, n)
+	for i := 0; i < n; i++ {
+		dist[i] = UndefDist
+		prev[i] = Undef
+	}
+	dist[source] = 0
+	if visitor != nil {
+		visitor.OnDiscover(source)
+	}
+
+	stopped := false
+	queue := []int{source}
+	for len(queue) > 0 && !stopped {
+		u := queue[0]
+		queue = queue[1:]
+		action := Continue
+		if visitor != nil {
+			action = visitor.OnSettle(u, dist[u])
+		}
+		if action == Stop {
+			break
+		}
+		if action == Skip {
+			continue
+		}
+		g.Neighbors(u, func(e Edge) bool {
+			if visitor != nil {
+				switch visitor.OnRelax(e) {
+				case Skip:
+					return true
+				case Stop:
+					stopped = true
+					return false
+				}
+			}
+			if dist[e.To] == UndefDist {
+				if visitor != nil {
+					visitor.OnDiscover(e.To)
+				}
+				dist[e.To] = dist[u] + 1
+				prev[e.To] = u
+				queue = append(queue, e.To)
+			}
+			return true
+		})
+		if err := m.settle(); err != nil {
+			return nil, err
+		}
+	}
+	m.finish()
+	return &Path{source: source, dist: dist, prev: prev}, nil
+}
+
+// AStar finds the shortest path from source to target guided by a
+// heuristic, which must never overestimate the remaining cost. The search
+// keeps state only for the vertexes it reaches, so it suits implicit graphs
+// too large for a full Dijkstra. A heuristic which isn't also consistent
+// may make the search expand some vertexes again, settling them anew. The
+// path is returned from target back to source, like Path.BuildPath does,
+// together with its cost.
+func AStar(g Interface, source int, target int, heuristic func(vertex int) int, opts ...Option) ([]int, int, error) {
+	return AStarContext(context.Background(), g, source, target, heuristic, opts...)
+}
+
+// AStarContext is AStar which gives up with ctx.Err() once the context is
+// done.
+func AStarContext(ctx context.Context, g Interface, source int, target int, heuristic func(vertex int) int, opts ...Option) ([]int, int, error) {
+	n := g.VertexCount()
+	if source < 0 || source >= n || target < 0 || target >= n {
+		return nil, UndefDist, ErrVertexRange
+	}
+	o := newOptions(opts)
+	g, err := o.view(g)
+	if err != nil {
+		return nil, UndefDist, err
+	}
+	m, err := newMonitor(ctx, o)
+	if err != nil {
+		return nil, UndefDist, err
+	}
+	visitor := o.visitor
+	if visitor != nil {
+		visitor.OnDiscover(source)
+	}
+	dist := map[int]int{source: 0}
+	prev := map[int]int{source: Undef}
+	closed := make(map[int]bool)
+	open := minHeap{}
+	open.push(source, heuristic(source))
+
+	for len(open) > 0 {
+		u := open.pop().vertex
+		if closed[u] {
+			continue
+		}
+		closed[u] = true
+		action := Continue
+		if visitor != nil {
+			action = visitor.OnSettle(u, dist[u])
+		}
+		if action == Stop {
+			break
+		}
+		if u == target {
+			path := make([]int, 0)
+			for v := target; v != Undef; v = prev[v] {
+				path = append(path, v)
+			}
+			m.finish()
+			return path, dist[target], nil
+		}
+		if action == Skip {
+			continue
+		}
+		stopped := false
+		g.Neighbors(u, func(e Edge) bool {
+			if visitor != nil {
+				switch visitor.OnRelax(e) {
+				case Skip:
+					return true
+				case Stop:
+					stopped = true
+					return false
+				}
+			}
+			alt := dist[u] + e.Cost
+			d, ok := dist[e.To]
+			if !ok && visitor != nil {
+				visitor.OnDiscover(e.To)
+			}
+			if !ok || alt < d {
+				dist[e.To] = alt
+				prev[e.To] = u
+				// only an inconsistent heuristic closes a vertex too early
+				delete(closed, e.To)
+				open.push(e.To, alt+heuristic(e.To))
+			}
+			return true
+		})
+		if stopped {
+			break
+		}
+		if err := m.settle(); err != nil {
+			return nil, UndefDist, err
+		}
+	}
+	m.finish()
+	return nil, UndefDist, ErrNoPath
+}
@@ -0,0 +1,79 @@
+package dijkstra_test
+
+import (
+	"reflect"
+	"testing"
+
+	algo "github.com/octo47/gomisc/algo/dijkstra"
+)
+
+func TestBFS(t *testing.T) {
+	graph := algo.NewDirectedGraph()
+	graph.AddVertexes(5)
+	graph.AddEdge(0, 1, 10)
+	graph.AddEdge(1, 2, 10)
+	graph.AddEdge(0, 3, 1)
+	graph.AddEdge(3, 4, 1)
+	graph.AddEdge(4, 2, 1)
+
+	path, err := algo.BFS(graph, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hops := path.PathCost(2); hops != 2 {
+		t.Error("Wrong hop count", hops)
+	}
+	if pathTo2 := path.BuildPath(2); !reflect.DeepEqual(pathTo2, []int{2, 1, 0}) {
+		t.Error("Wrong path calculated", pathTo2)
+	}
+}
+
+func TestAStarAgreesWithDijkstra(t *testing.T) {
+	graph := algo.NewUndirectedGraph()
+	graph.AddVertexes(6)
+	graph.AddEdge(0, 1, 7)
+	graph.AddEdge(0, 2, 9)
+	graph.AddEdge(0, 5, 14)
+	graph.AddEdge(1, 2, 10)
+	graph.AddEdge(1, 3, 15)
+	graph.AddEdge(2, 3, 11)
+	graph.AddEdge(2, 5, 2)
+	graph.AddEdge(3, 4, 6)
+	graph.AddEdge(4, 5, 9)
+
+	path, _ := algo.Dijkstra(graph, 0)
+	zero := func(int) int { return 0 }
+	for target := 0; target < 6; target++ {
+		route, cost, err := algo.AStar(graph, 0, target, zero)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cost != path.PathCost(target) || !reflect.DeepEqual(route, path.BuildPath(target)) {
+			t.Error("A* disagrees with Dijkstra", target, route, cost)
+		}
+	}
+}
+
+func TestAStarInconsistentHeuristic(t *testing.T) {
+	graph := algo.NewDirectedGraph()
+	graph.AddVertexes(4)
+	graph.AddEdge(0, 1, 1)
+	graph.AddEdge(1, 2, 1)
+	graph.AddEdge(0, 2, 3)
+	graph.AddEdge(2, 3, 3)
+
+	// admissible, but closes 2 through the direct edge first
+	heuristic := func(vertex int) int {
+		if vertex == 1 {
+			return 4
+		}
+		return 0
+	}
+	route, cost, err := algo.AStar(graph, 0, 3, heuristic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cost != 5 || !reflect.DeepEqual(route, []int{3, 2, 1, 0}) {
+		t.Error("Wrong path", route, cost)
+	}
+}
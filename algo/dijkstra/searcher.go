@@ -0,0 +1,147 @@
+package dijkstra
+
+// Searcher answers repeated shortest path queries on one graph, reusing
+// its buffers so that queries don't allocate once it has warmed up. The
+// buffers are tagged with a query version instead of being cleared, so a
+// query only pays for the vertexes it touches. Costs must be non-negative,
+// a query meeting a negative one fails with ErrNegativeCost.
+// A Searcher is not safe for concurrent use, keep one per goroutine.
+type Searcher struct {
+	g       Interface
+	dist    []int
+	prev    []int
+	reached []uint32 // dist and prev are valid when equal to version
+	settled []uint32
+	version uint32
+	heap    minHeap
+	source  int
+	u       int
+	relax   func(e Edge) bool
+	failed  error
+}
+
+func NewSearcher(g Interface) *Searcher {
+	s := &Searcher{g: g, source: Undef}
+	s.relax = func(e Edge) bool {
+		if e.Cost < 0 {
+			s.failed = ErrNegativeCost
+			return false
+		}
+		v := e.To
+		if s.settled[v] == s.version {
+			return true
+		}
+		if alt := s.dist[s.u] + e.Cost; s.reached[v] != s.version || alt < s.dist[v] {
+			s.reached[v] = s.version
+			s.dist[v] = alt
+			s.prev[v] = s.u
+			s.push(v, alt)
+		}
+		return true
+	}
+	return s
+}
+
+// Dijkstra finds the shortest paths from source to every vertex. The
+// results stay available until the next query.
+func (s *Searcher) Dijkstra(source int) error {
+	return s.search(source, Undef)
+}
+
+// ShortestPath finds the shortest path from source to target and returns
+// its cost, UndefDist if target is unreachable. The search stops as soon as
+// target is settled, so distances to farther vertexes are unknown. It only
+// stops early on a CostBounder ruling out negative costs, other graphs are
+// searched whole so that any reachable negative cost is found.
+func (s *Searcher) ShortestPath(source int, target int) (int, error) {
+	if err := s.search(source, target); err != nil {
+		return UndefDist, err
+	}
+	return s.PathCost(target), nil
+}
+
+// PathCost returns the cost of the path to target found by the last query.
+func (s *Searcher) PathCost(target int) int {
+	if target < 0 || target >= len(s.reached) || s.reached[target] != s.version {
+		return UndefDist
+	}
+	return s.dist[target]
+}
+
+// BuildPath appends the path to target found by the last query to buf,
+// from target back to source like Path.BuildPath, and returns the result.
+// Pass a reused buf to avoid allocating.
+func (s *Searcher) BuildPath(target int, buf []int) []int {
+	if s.PathCost(target) == UndefDist {
+		return buf
+	}
+	for u := target; u != Undef; u = s.prev[u] {
+		buf = append(buf, u)
+	}
+	return buf
+}
+
+func (s *Searcher) search(source int, target int) error {
+	n := s.g.VertexCount()
+	if source < 0 || source >= n {
+		return ErrVertexRange
+	}
+	if b, ok := s.g.(CostBounder); !ok {
+		target = Undef
+	} else if min, _ := b.CostBounds(); min < 0 {
+		target = Undef
+	}
+	s.reset(n)
+	s.failed = nil
+	s.source = source
+	s.reached[source] = s.version
+	s.dist[source] = 0
+	s.prev[source] = Undef
+	s.push(source, 0)
+
+	for len(s.heap) > 0 {
+		item := s.pop()
+		u := item.vertex
+		if s.settled[u] == s.version || item.priority > s.dist[u] {
+			continue
+		}
+		s.settled[u] = s.version
+		if u == target {
+			break
+		}
+		s.u = u
+		s.g.Neighbors(u, s.relax)
+		if s.failed != nil {
+			return s.failed
+		}
+	}
+	return nil
+}
+
+// reset starts a new query version, growing the buffers with the graph
+func (s *Searcher) reset(n int) {
+	if n > len(s.dist) {
+		s.dist = append(s.dist, make([]int, n-len(s.dist))...)
+		s.prev = append(s.prev, make([]int, n-len(s.prev))...)
+		s.reached = append(s.reached, make([]uint32, n-len(s.reached))...)
+		s.settled = append(s.settled, make([]uint32, n-len(s.settled))...)
+	}
+	s.heap = s.heap[:0]
+	s.version++
+	if s.version == 0 {
+		// stamps wrapped around, old ones could look current
+		for i := range s.reached {
+			s.reached[i] = 0
+			s.settled[i] = 0
+		}
+		s.version = 1
+	}
+}
+
+func (s *Searcher) push(vertex int, priority int) {
+	s.heap.push(vertex, priority)
+}
+
+func (s *Searcher) pop() queueItem {
+	return s.heap.pop()
+}
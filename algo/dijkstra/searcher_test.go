@@ -0,0 +1,94 @@
+package dijkstra_test
+
+import (
+	"reflect"
+	"testing"
+
+	algo "github.com/octo47/gomisc/algo/dijkstra"
+	"github.com/octo47/gomisc/algo/dijkstra/graphgen"
+)
+
+func TestSearcherAgreesWithDijkstra(t *testing.T) {
+	graph := graphgen.ErdosRenyi(300, 0.02, 50, 3)
+	s := algo.NewSearcher(graph)
+	buf := make([]int, 0)
+	for _, source := range []int{0, 17, 0, 299, 42} {
+		want, _ := graph.Dijkstra(source)
+		if err := s.Dijkstra(source); err != nil {
+			t.Fatal(err)
+		}
+		for v := 0; v < graph.VertexCount(); v++ {
+			if s.PathCost(v) != want.PathCost(v) {
+				t.Fatal("Searcher disagrees with Dijkstra", source, v)
+			}
+			if cost := want.PathCost(v); cost == algo.UndefDist {
+				continue
+			}
+			buf = s.BuildPath(v, buf[:0])
+			if buf[0] != v || buf[len(buf)-1] != source {
+				t.Fatal("Wrong path", source, v, buf)
+			}
+		}
+	}
+}
+
+func TestSearcherShortestPath(t *testing.T) {
+	graph := graphgen.Grid(20, 20, 0, 1)
+	s := algo.NewSearcher(graph)
+	cost, err := s.ShortestPath(0, 21)
+	if err != nil || cost != 2 {
+		t.Error("Wrong path cost", cost, err)
+	}
+	if cost := s.PathCost(399); cost != algo.UndefDist {
+		t.Error("Search must stop at the target", cost)
+	}
+
+	// the graph grows between queries
+	v := graph.AddVertex()
+	graph.AddEdge(399, v, 5, false)
+	if cost, _ := s.ShortestPath(0, v); cost != 43 {
+		t.Error("Wrong path cost", cost)
+	}
+	if path := s.BuildPath(v, nil); len(path) != 40 {
+		t.Error("Wrong path", path)
+	}
+	if path := s.BuildPath(21, []int{7}); !reflect.DeepEqual(path[:1], []int{7}) {
+		t.Error("Path must be appended", path)
+	}
+}
+
+func TestSearcherNegativeCost(t *testing.T) {
+	graph := algo.NewGraph()
+	graph.AddVertexes(3)
+	graph.AddEdge(0, 1, 3, false)
+	graph.AddEdge(0, 2, 2, false)
+	graph.AddEdge(1, 2, -2, false)
+
+	s := algo.NewSearcher(graph)
+	if _, err := s.ShortestPath(0, 2); err != algo.ErrNegativeCost {
+		t.Error("Negative cost must be rejected", err)
+	}
+	if err := s.Dijkstra(0); err != algo.ErrNegativeCost {
+		t.Error("Negative cost must be rejected", err)
+	}
+
+	// the next query starts over
+	graph.DelEdge(1, 2)
+	if cost, err := s.ShortestPath(0, 2); err != nil || cost != 2 {
+		t.Error("Wrong path cost", cost, err)
+	}
+}
+
+func TestSearcherZeroAllocs(t *testing.T) {
+	graph := graphgen.Grid(50, 50, 0.1, 1)
+	s := algo.NewSearcher(graph)
+	buf := make([]int, 0, 2500)
+	query := func() {
+		s.ShortestPath(0, 2499)
+		buf = s.BuildPath(2499, buf[:0])
+	}
+	query()
+	if allocs := testing.AllocsPerRun(100, query); allocs != 0 {
+		t.Error("Steady state queries must not allocate", allocs)
+	}
+}
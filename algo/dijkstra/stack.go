@@ -26,6 +26,19 @@ func (s *Stack) Pop() (int, error) {
 	return rv, nil
 }
 
+// Peek returns the top of the stack without removing it
+func (s *Stack) Peek() (int, error) {
+	l := len(s.stack)
+	if l == 0 {
+		return 0, errors.New("Stack is empty")
+	}
+	return s.stack[l-1], nil
+}
+
+func (s *Stack) Len() int {
+	return len(s.stack)
+}
+
 func (s *Stack) AsSlice() []int {
 	cp := make([]int, len(s.stack))
 	copy(cp, s.stack)
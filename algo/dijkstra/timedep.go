@@ -0,0 +1,223 @@
+package dijkstra
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+var (
+	ErrNegativeCost = errors.New("Edge cost must be non-negative")
+	ErrBreakpoints  = errors.New("Breakpoints must be sorted and of equal count")
+	ErrNotFIFO      = errors.New("Travel time violates FIFO: leaving later must not arrive earlier")
+)
+
+// TravelTime is the cost of a time-dependent edge as a function of the
+// time its tail is left. It must have the FIFO property: leaving later
+// never arrives earlier, that is t + At(t) never decreases.
+type TravelTime interface {
+	At(departure int) int
+}
+
+// ConstantTime is a travel time which doesn't depend on departure.
+type ConstantTime int
+
+func (c ConstantTime) At(departure int) int {
+	return int(c)
+}
+
+// PiecewiseLinear is a travel time interpolated between breakpoints and
+// constant before the first and after the last one. Two breakpoints at the
+// same time make a jump, the travel time at the jump is the earlier one:
+// the last ferry of the slot is still caught.
+type PiecewiseLinear struct {
+	times     []int
+	durations []int
+}
+
+// NewPiecewiseLinear builds a travel time from sorted breakpoints, and
+// fails unless it is FIFO: no segment may fall faster than time passes, and
+// no jump may go down.
+func NewPiecewiseLinear(times []int, durations []int) (*PiecewiseLinear, error) {
+	if len(times) == 0 || len(times) != len(durations) {
+		return nil, ErrBreakpoints
+	}
+	for i := 1; i < len(times); i++ {
+		dt := times[i] - times[i-1]
+		if dt < 0 {
+			return nil, ErrBreakpoints
+		}
+		if durations[i]-durations[i-1] < -dt {
+			return nil, ErrNotFIFO
+		}
+	}
+	return &PiecewiseLinear{times: times, durations: durations}, nil
+}
+
+func (p *PiecewiseLinear) At(departure int) int {
+	i := sort.SearchInts(p.times, departure)
+	switch {
+	case i == len(p.times):
+		return p.durations[i-1]
+	case p.times[i] == departure || i == 0:
+		return p.durations[i]
+	}
+	t0, t1 := p.times[i-1], p.times[i]
+	d0, d1 := p.durations[i-1], p.durations[i]
+	return d0 + (d1-d0)*(departure-t0)/(t1-t0)
+}
+
+type tdEdge struct {
+	id     EdgeID
+	target int
+	travel TravelTime
+}
+
+// TimeDependentGraph is a directed graph whose edge costs depend on the
+// time the edge is entered.
+type TimeDependentGraph struct {
+	edges [][]tdEdge
+	// travel times by edge id, for the search
+	travels []TravelTime
+}
+
+// FIFOError reports an edge where leaving later arrives earlier.
+type FIFOError struct {
+	Edge EdgeID
+	Time int
+}
+
+func (e *FIFOError) Error() string {
+	return fmt.Sprintf("edge %d violates FIFO: leaving at %d arrives later than at %d", e.Edge, e.Time, e.Time+1)
+}
+
+func NewTimeDependentGraph() *TimeDependentGraph {
+	return &TimeDependentGraph{
+		edges: make([][]tdEdge, 0),
+	}
+}
+
+// add vertex to graph and return its index
+func (g *TimeDependentGraph) AddVertex() int {
+	g.edges = append(g.edges, make([]tdEdge, 0))
+	return len(g.edges) - 1
+}
+
+func (g *TimeDependentGraph) AddVertexes(count int) {
+	for i := 0; i < count; i++ {
+		g.edges = append(g.edges, make([]tdEdge, 0))
+	}
+}
+
+// AddEdge adds a directed edge with the given travel time and returns its id.
+func (g *TimeDependentGraph) AddEdge(from int, to int, travel TravelTime) EdgeID {
+	id := EdgeID(len(g.travels))
+	g.travels = append(g.travels, travel)
+	g.edges[from] = append(g.edges[from], tdEdge{id: id, target: to, travel: travel})
+	return id
+}
+
+// ValidateFIFO checks every edge for the FIFO property at every departure
+// time in [from, until], and returns a *FIFOError for the first violation.
+// Travel times built with NewPiecewiseLinear are FIFO already.
+func (g *TimeDependentGraph) ValidateFIFO(from int, until int) error {
+	for u := range g.edges {
+		for _, e := range g.edges[u] {
+			if _, ok := e.travel.(*PiecewiseLinear); ok {
+				continue
+			}
+			if _, ok := e.travel.(ConstantTime); ok {
+				continue
+			}
+			arrival := from + e.travel.At(from)
+			for t := from; t < until; t++ {
+				next := t + 1 + e.travel.At(t+1)
+				if next < arrival {
+					return &FIFOError{Edge: e.id, Time: t}
+				}
+				arrival = next
+			}
+		}
+	}
+	return nil
+}
+
+// EarliestArrival finds the earliest arrival time at every vertex when
+// leaving source at departure. PathCost of the result is the arrival time,
+// UndefDist for unreachable vertexes. Waiting at vertexes never helps on
+// FIFO edges, so the label-setting search is exact.
+func (g *TimeDependentGraph) EarliestArrival(source int, departure int) (*Path, error) {
+	return g.EarliestArrivalContext(context.Background(), source, departure)
+}
+
+// EarliestArrivalContext is EarliestArrival which gives up with ctx.Err()
+// once the context is done. Of the options it honors WithProgress and
+// WithVisitor, the others fail with ErrUnsupportedOption.
+func (g *TimeDependentGraph) EarliestArrivalContext(ctx context.Context, source int, departure int, opts ...Option) (*Path, error) {
+	n := len(g.edges)
+	if source < 0 || source >= n {
+		return nil, ErrVertexRange
+	}
+	o := newOptions(opts)
+	if err := o.only(optProgress | optVisitor); err != nil {
+		return nil, err
+	}
+	m, err := newMonitor(ctx, o)
+	if err != nil {
+		return nil, err
+	}
+	s := newSearch(n, source, departure, arrivalTimes{travels: g.travels}, o, func(time int) int { return time })
+	for i := range s.weight {
+		if i != source {
+			s.weight[i] = UndefDist
+		}
+	}
+	if err := s.run(m, timeDependentView{g}, source, &binaryQueue{}); err != nil {
+		return nil, err
+	}
+	return &Path{source: source, dist: s.weight, prev: s.prev}, nil
+}
+
+// arrivalTimes is the arithmetic of EarliestArrival: extending a path by
+// an edge adds the travel time at the time the path arrives
+type arrivalTimes struct {
+	travels []TravelTime
+}
+
+func (a arrivalTimes) relax(time int, e Edge, old int, reached bool) (int, bool, error) {
+	travel := a.travels[e.ID].At(time)
+	if travel < 0 {
+		return 0, false, ErrNegativeCost
+	}
+	alt := time + travel
+	return alt, !reached || alt < old, nil
+}
+
+// timeDependentView shows the edges of a time-dependent graph to the
+// search, which looks their travel times up by id
+type timeDependentView struct {
+	g *TimeDependentGraph
+}
+
+func (v timeDependentView) VertexCount() int {
+	return len(v.g.edges)
+}
+
+func (v timeDependentView) Neighbors(vertex int, fn func(e Edge) bool) {
+	for _, e := range v.g.edges[vertex] {
+		if !fn(Edge{ID: e.id, From: vertex, To: e.target}) {
+			return
+		}
+	}
+}
+
+// EdgeCost tells only whether there is an edge, its cost depends on time
+func (v timeDependentView) EdgeCost(from int, to int) (int, bool) {
+	for _, e := range v.g.edges[from] {
+		if e.target == to {
+			return 0, true
+		}
+	}
+	return 0, false
+}
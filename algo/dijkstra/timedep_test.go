@@ -0,0 +1,96 @@
+package dijkstra_test
+
+import (
+	"reflect"
+	"testing"
+
+	algo "github.com/octo47/gomisc/algo/dijkstra"
+)
+
+func TestPiecewiseLinear(t *testing.T) {
+	// ferry leaves at 10 and 20 and sails for 5
+	ferry, err := algo.NewPiecewiseLinear([]int{0, 10, 10, 20}, []int{15, 5, 15, 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for departure, want := range map[int]int{-5: 15, 0: 15, 4: 11, 10: 5, 11: 14, 20: 5, 30: 5} {
+		if got := ferry.At(departure); got != want {
+			t.Error("Wrong travel time", departure, got, want)
+		}
+	}
+
+	if _, err := algo.NewPiecewiseLinear([]int{0, 10}, []int{30, 5}); err != algo.ErrNotFIFO {
+		t.Error("Overtaking travel time must be rejected", err)
+	}
+	if _, err := algo.NewPiecewiseLinear([]int{0, 10, 10}, []int{5, 15, 5}); err != algo.ErrNotFIFO {
+		t.Error("Downward jump must be rejected", err)
+	}
+	if _, err := algo.NewPiecewiseLinear([]int{10, 0}, []int{5, 5}); err != algo.ErrBreakpoints {
+		t.Error("Unsorted breakpoints must be rejected", err)
+	}
+}
+
+func TestEarliestArrival(t *testing.T) {
+	// a road around the bay takes 12, the ferry across leaves at 10 and 20
+	ferry, _ := algo.NewPiecewiseLinear([]int{0, 10, 10, 20}, []int{15, 5, 15, 5})
+	// rush hour on the way to the pier from 0 to 10
+	rush, _ := algo.NewPiecewiseLinear([]int{0, 5, 10}, []int{2, 6, 2})
+
+	if ferry == nil || rush == nil {
+		t.Fatal("Travel times must be FIFO")
+	}
+
+	graph := algo.NewTimeDependentGraph()
+	graph.AddVertexes(3)
+	graph.AddEdge(0, 1, rush)
+	graph.AddEdge(1, 2, ferry)
+	graph.AddEdge(0, 2, algo.ConstantTime(12))
+
+	cases := []struct {
+		departure int
+		arrival   int
+		path      []int
+	}{
+		{0, 12, []int{2, 0}},     // pier at 2, ferry at 10
+		{4, 15, []int{2, 1, 0}},  // pier at 9, catches the ferry at 10
+		{6, 18, []int{2, 0}},     // misses the ferry, road is faster
+		{15, 25, []int{2, 1, 0}}, // pier at 17, ferry at 20
+	}
+	for _, c := range cases {
+		path, err := graph.EarliestArrival(0, c.departure)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if arrival := path.PathCost(2); arrival != c.arrival {
+			t.Error("Wrong arrival", c.departure, arrival)
+		}
+		if route := path.BuildPath(2); !reflect.DeepEqual(route, c.path) {
+			t.Error("Wrong route", c.departure, route)
+		}
+	}
+}
+
+type overtaking struct{}
+
+func (overtaking) At(departure int) int {
+	if departure < 5 {
+		return 10
+	}
+	return 1
+}
+
+func TestValidateFIFO(t *testing.T) {
+	graph := algo.NewTimeDependentGraph()
+	graph.AddVertexes(2)
+	graph.AddEdge(0, 1, algo.ConstantTime(3))
+	bad := graph.AddEdge(1, 0, overtaking{})
+
+	err := graph.ValidateFIFO(0, 100)
+	fifo, ok := err.(*algo.FIFOError)
+	if !ok || fifo.Edge != bad || fifo.Time != 4 {
+		t.Error("Expected FIFO violation", err)
+	}
+	if err := graph.ValidateFIFO(10, 100); err != nil {
+		t.Error("Edge is FIFO after the switch", err)
+	}
+}
@@ -0,0 +1,114 @@
+package dijkstra
+
+// Transpose returns the graph with every edge reversed. Edge ids are kept,
+// edges added with bidir stay so.
+func (g *Graph) Transpose() *Graph {
+	out := newGraphOf(g.VertexCount())
+	g.eachEdge(func(id EdgeID, ends edgeEnds, cost int) {
+		out.padEnds(int(id))
+		out.AddEdge(ends.to, ends.from, cost, ends.bidir)
+	})
+	out.padEnds(len(g.ends))
+	return out
+}
+
+// InducedSubgraph returns the graph made of the given vertexes and the
+// edges between them, together with the original vertex of every vertex
+// of the subgraph. Vertexes are numbered in the order given, repeated ones
+// are dropped. Edge ids are kept.
+func (g *Graph) InducedSubgraph(vertexes []int) (*Graph, []int) {
+	index := make([]int, g.VertexCount())
+	for i := range index {
+		index[i] = Undef
+	}
+	original := make([]int, 0, len(vertexes))
+	for _, v := range vertexes {
+		if index[v] == Undef {
+			index[v] = len(original)
+			original = append(original, v)
+		}
+	}
+
+	out := newGraphOf(len(original))
+	g.eachEdge(func(id EdgeID, ends edgeEnds, cost int) {
+		from, to := index[ends.from], index[ends.to]
+		if from == Undef || to == Undef {
+			return
+		}
+		out.padEnds(int(id))
+		out.AddEdge(from, to, cost, ends.bidir)
+	})
+	out.padEnds(len(g.ends))
+	return out, original
+}
+
+// FilterEdges returns the graph holding the edges pred returns true for.
+// An edge added with bidir is passed to pred once, in the direction it was
+// added. Edge ids are kept.
+func (g *Graph) FilterEdges(pred func(e Edge) bool) *Graph {
+	out := newGraphOf(g.VertexCount())
+	g.eachEdge(func(id EdgeID, ends edgeEnds, cost int) {
+		if !pred(Edge{ID: id, From: ends.from, To: ends.to, Cost: cost}) {
+			return
+		}
+		out.padEnds(int(id))
+		out.AddEdge(ends.from, ends.to, cost, ends.bidir)
+	})
+	out.padEnds(len(g.ends))
+	return out
+}
+
+// Union returns a graph holding the edges of both graphs, parallel edges
+// included. It has as many vertexes as the larger of the two. Edges of g
+// keep their ids, edges of other get new ones: the returned slice maps an
+// edge id of other to its id in the union, Undef for removed edges.
+func (g *Graph) Union(other *Graph) (*Graph, []EdgeID) {
+	n := g.VertexCount()
+	if other.VertexCount() > n {
+		n = other.VertexCount()
+	}
+	out := newGraphOf(n)
+	g.eachEdge(func(id EdgeID, ends edgeEnds, cost int) {
+		out.padEnds(int(id))
+		out.AddEdge(ends.from, ends.to, cost, ends.bidir)
+	})
+	out.padEnds(len(g.ends))
+
+	mapping := make([]EdgeID, len(other.ends))
+	for i := range mapping {
+		mapping[i] = EdgeID(Undef)
+	}
+	other.eachEdge(func(id EdgeID, ends edgeEnds, cost int) {
+		mapping[id] = out.AddEdge(ends.from, ends.to, cost, ends.bidir)
+	})
+	return out, mapping
+}
+
+func newGraphOf(vertexes int) *Graph {
+	g := NewGraph()
+	g.AddVertexes(vertexes)
+	return g
+}
+
+// eachEdge calls fn for every live edge in id order
+func (g *Graph) eachEdge(fn func(id EdgeID, ends edgeEnds, cost int)) {
+	costs := make([]int, len(g.ends))
+	for _, a := range g.edges {
+		for _, e := range a {
+			costs[e.id] = e.cost
+		}
+	}
+	for id, ends := range g.ends {
+		if ends.live {
+			fn(EdgeID(id), ends, costs[id])
+		}
+	}
+}
+
+// padEnds fills the edge id space up to n with removed edges, so the next
+// edge added gets id n
+func (g *Graph) padEnds(n int) {
+	for len(g.ends) < n {
+		g.ends = append(g.ends, edgeEnds{})
+	}
+}
@@ -0,0 +1,129 @@
+package dijkstra_test
+
+import (
+	"reflect"
+	"testing"
+
+	algo "github.com/octo47/gomisc/algo/dijkstra"
+)
+
+// edgesOf lists the edges of a graph as seen by Neighbors
+func edgesOf(g algo.Interface) []algo.Edge {
+	edges := make([]algo.Edge, 0)
+	for v := 0; v < g.VertexCount(); v++ {
+		g.Neighbors(v, func(e algo.Edge) bool {
+			edges = append(edges, e)
+			return true
+		})
+	}
+	return edges
+}
+
+func transformGraph() *algo.Graph {
+	graph := algo.NewGraph()
+	graph.AddVertexes(4)
+	graph.AddEdge(0, 1, 1, false)
+	removed := graph.AddEdge(0, 2, 9, false)
+	graph.AddEdge(1, 2, 2, true)
+	graph.AddEdge(2, 3, 3, false)
+	graph.DelEdgeByID(removed)
+	return graph
+}
+
+func TestTranspose(t *testing.T) {
+	graph := transformGraph()
+	reversed := graph.Transpose()
+	want := []algo.Edge{
+		{ID: 0, From: 1, To: 0, Cost: 1},
+		{ID: 2, From: 1, To: 2, Cost: 2},
+		{ID: 2, From: 2, To: 1, Cost: 2},
+		{ID: 3, From: 3, To: 2, Cost: 3},
+	}
+	if got := edgesOf(reversed); !reflect.DeepEqual(got, want) {
+		t.Error("Wrong edges", got)
+	}
+	if err := reversed.DelEdgeByID(1); err != algo.ErrNoEdge {
+		t.Error("Removed edge came back", err)
+	}
+	path, _ := reversed.Dijkstra(3)
+	if cost := path.PathCost(0); cost != 6 {
+		t.Error("Wrong backward cost", cost)
+	}
+	if len(edgesOf(graph)) != 4 || graph.VertexCount() != 4 {
+		t.Error("Original graph changed")
+	}
+}
+
+func TestInducedSubgraph(t *testing.T) {
+	graph := transformGraph()
+	sub, original := graph.InducedSubgraph([]int{3, 2, 3, 1})
+	if !reflect.DeepEqual(original, []int{3, 2, 1}) {
+		t.Error("Wrong vertex mapping", original)
+	}
+	want := []algo.Edge{
+		{ID: 2, From: 1, To: 2, Cost: 2},
+		{ID: 3, From: 1, To: 0, Cost: 3},
+		{ID: 2, From: 2, To: 1, Cost: 2},
+	}
+	if got := edgesOf(sub); !reflect.DeepEqual(got, want) {
+		t.Error("Wrong edges", got)
+	}
+	if err := sub.SetEdgeCost(2, 5); err != nil {
+		t.Error("Kept edge id not found", err)
+	}
+	if cost, _ := graph.EdgeCost(1, 2); cost != 2 {
+		t.Error("Original graph changed", cost)
+	}
+}
+
+func TestFilterEdges(t *testing.T) {
+	graph := transformGraph()
+	seen := 0
+	cheap := graph.FilterEdges(func(e algo.Edge) bool {
+		seen++
+		return e.Cost < 3
+	})
+	if seen != 3 {
+		t.Error("Predicate called", seen, "times")
+	}
+	if _, ok := cheap.EdgeCost(2, 3); ok {
+		t.Error("Filtered edge kept")
+	}
+	if cost, ok := cheap.EdgeCost(2, 1); !ok || cost != 2 {
+		t.Error("Bidir edge lost", cost)
+	}
+	if min, max := cheap.CostBounds(); min != 0 || max != 2 {
+		t.Error("Wrong cost bounds", min, max)
+	}
+}
+
+func TestUnion(t *testing.T) {
+	graph := transformGraph()
+	other := algo.NewGraph()
+	other.AddVertexes(6)
+	dropped := other.AddEdge(4, 5, 1, false)
+	other.AddEdge(3, 4, 1, false)
+	other.AddEdge(0, 1, 7, false)
+	other.DelEdgeByID(dropped)
+
+	union, mapping := graph.Union(other)
+	if union.VertexCount() != 6 {
+		t.Error("Wrong vertex count", union.VertexCount())
+	}
+	if !reflect.DeepEqual(mapping, []algo.EdgeID{algo.EdgeID(algo.Undef), 4, 5}) {
+		t.Error("Wrong edge mapping", mapping)
+	}
+	if cost, _ := union.EdgeCost(0, 1); cost != 1 {
+		t.Error("Parallel edge lost", cost)
+	}
+	path, _ := union.Dijkstra(0)
+	if cost := path.PathCost(4); cost != 7 {
+		t.Error("Wrong path cost", cost)
+	}
+	if err := union.DelEdgeByID(mapping[2]); err != nil {
+		t.Error(err)
+	}
+	if cost, _ := union.EdgeCost(0, 1); cost != 1 {
+		t.Error("Wrong edge removed", cost)
+	}
+}
@@ -0,0 +1,52 @@
+package dijkstra
+
+// Action tells a search how to go on after a visitor hook.
+type Action int
+
+const (
+	// Continue goes on as usual.
+	Continue Action = iota
+	// Skip ignores the edge when returned from OnRelax, and keeps the
+	// vertex from expanding its edges when returned from OnSettle.
+	Skip
+	// Stop ends the search, which returns whatever it has found so far.
+	Stop
+)
+
+// Visitor observes and steers a search, see WithVisitor.
+type Visitor interface {
+	// OnDiscover is called when a vertex is reached for the first time.
+	OnDiscover(vertex int)
+	// OnRelax is called for every edge leaving a settled vertex, before
+	// the edge is used.
+	OnRelax(e Edge) Action
+	// OnSettle is called when the distance of a vertex becomes final.
+	OnSettle(vertex int, dist int) Action
+}
+
+// WeightVisitor is a Visitor which Search tells the exact weight of a
+// settled vertex, calling OnSettleWeight instead of OnSettle.
+type WeightVisitor interface {
+	Visitor
+	OnSettleWeight(vertex int, weight float64) Action
+}
+
+// BaseVisitor does nothing and lets the search continue. Embed it to
+// implement only the hooks you need.
+type BaseVisitor struct{}
+
+func (BaseVisitor) OnDiscover(vertex int)                {}
+func (BaseVisitor) OnRelax(e Edge) Action                { return Continue }
+func (BaseVisitor) OnSettle(vertex int, dist int) Action { return Continue }
+
+// WithVisitor makes Dijkstra, BFS, AStar, EarliestArrival and Search call
+// the visitor hooks as the search proceeds. EarliestArrival passes arrival
+// times to OnSettle, Search its weights rounded to int unless the visitor
+// is a WeightVisitor. ShortestPathMaxHops, ParetoPaths and the flows don't
+// settle vertexes one by one and fail with ErrUnsupportedOption; Searcher
+// takes no options at all.
+func WithVisitor(v Visitor) Option {
+	return func(o *options) {
+		o.visitor = v
+	}
+}
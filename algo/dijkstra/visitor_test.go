@@ -0,0 +1,218 @@
+package dijkstra_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	algo "github.com/octo47/gomisc/algo/dijkstra"
+	"github.com/octo47/gomisc/algo/dijkstra/graphgen"
+)
+
+// counter counts hook calls and settles vertexes up to a limit
+type counter struct {
+	discovered int
+	relaxed    int
+	settled    int
+	limit      int
+}
+
+func (c *counter) OnDiscover(vertex int) {
+	c.discovered++
+}
+
+func (c *counter) OnRelax(e algo.Edge) algo.Action {
+	c.relaxed++
+	return algo.Continue
+}
+
+func (c *counter) OnSettle(vertex int, dist int) algo.Action {
+	c.settled++
+	if c.settled == c.limit {
+		return algo.Stop
+	}
+	return algo.Continue
+}
+
+func TestVisitorCounts(t *testing.T) {
+	grid := graphgen.Grid(10, 10, 0, 1)
+	for name, search := range map[string]func(v algo.Visitor) error{
+		"Dijkstra": func(v algo.Visitor) error {
+			_, err := grid.Dijkstra(0, algo.WithVisitor(v))
+			return err
+		},
+		"BFS": func(v algo.Visitor) error {
+			_, err := algo.BFS(grid, 0, algo.WithVisitor(v))
+			return err
+		},
+		"Search": func(v algo.Visitor) error {
+			_, err := algo.Search(grid, 0, algo.Shortest, algo.WithVisitor(v))
+			return err
+		},
+	} {
+		c := &counter{}
+		if err := search(c); err != nil {
+			t.Fatal(err)
+		}
+		// every vertex once, every one of the 180 grid edges both ways
+		if c.discovered != 100 || c.settled != 100 || c.relaxed != 360 {
+			t.Error("Wrong hook counts", name, c)
+		}
+	}
+}
+
+func TestVisitorStop(t *testing.T) {
+	grid := graphgen.Grid(10, 10, 0, 1)
+	c := &counter{limit: 5}
+	path, err := grid.Dijkstra(0, algo.WithVisitor(c))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.settled != 5 {
+		t.Error("Search must stop", c.settled)
+	}
+	if cost := path.PathCost(99); cost != algo.UndefDist {
+		t.Error("Far corner must not be reached", cost)
+	}
+
+	c = &counter{limit: 5}
+	if _, _, err := algo.AStar(grid, 0, 99, func(int) int { return 0 }, algo.WithVisitor(c)); err != algo.ErrNoPath {
+		t.Error("Stopped A* must not find a path", err)
+	}
+}
+
+// forbidden keeps searches out of a set of vertexes
+type forbidden struct {
+	algo.BaseVisitor
+	zone map[int]bool
+}
+
+func (f forbidden) OnRelax(e algo.Edge) algo.Action {
+	if f.zone[e.To] {
+		return algo.Skip
+	}
+	return algo.Continue
+}
+
+func TestVisitorForbiddenZone(t *testing.T) {
+	// wall off column 5 except its bottom cell
+	grid := graphgen.Grid(10, 10, 0, 1)
+	zone := forbidden{zone: make(map[int]bool)}
+	for y := 0; y < 9; y++ {
+		zone.zone[y*10+5] = true
+	}
+
+	path, err := grid.Dijkstra(0, algo.WithVisitor(zone))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cost := path.PathCost(9); cost != 27 {
+		t.Error("Path must go around the zone", cost)
+	}
+	if cost := path.PathCost(15); cost != algo.UndefDist {
+		t.Error("Zone must not be entered", cost)
+	}
+
+	route, cost, err := algo.AStar(grid, 0, 9, func(int) int { return 0 }, algo.WithVisitor(zone))
+	if err != nil || cost != 27 {
+		t.Error("A* must go around the zone", route, cost, err)
+	}
+}
+
+// arrivals records the times vertexes are settled at
+type arrivals struct {
+	algo.BaseVisitor
+	times map[int]int
+}
+
+func (a arrivals) OnSettle(vertex int, dist int) algo.Action {
+	a.times[vertex] = dist
+	return algo.Continue
+}
+
+func TestVisitorEarliestArrival(t *testing.T) {
+	graph := algo.NewTimeDependentGraph()
+	graph.AddVertexes(3)
+	graph.AddEdge(0, 1, algo.ConstantTime(5))
+	graph.AddEdge(1, 2, algo.ConstantTime(3))
+
+	a := arrivals{times: make(map[int]int)}
+	path, err := graph.EarliestArrivalContext(context.Background(), 0, 10, algo.WithVisitor(a))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(a.times, map[int]int{0: 10, 1: 15, 2: 18}) {
+		t.Error("Wrong arrival times", a.times)
+	}
+
+	c := &counter{limit: 2}
+	path, err = graph.EarliestArrivalContext(context.Background(), 0, 10, algo.WithVisitor(c))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if arrival := path.PathCost(2); arrival != algo.UndefDist {
+		t.Error("Search must stop", arrival)
+	}
+}
+
+func TestVisitorUnsupported(t *testing.T) {
+	grid := graphgen.Grid(3, 3, 0, 1)
+	visitor := algo.WithVisitor(algo.BaseVisitor{})
+	ctx := context.Background()
+	if _, err := algo.ShortestPathMaxHopsContext(ctx, grid, 0, 2, visitor); err != algo.ErrUnsupportedOption {
+		t.Error("ShortestPathMaxHops must reject the visitor", err)
+	}
+	if _, err := algo.MaxFlowContext(ctx, grid, 0, 8, visitor); err != algo.ErrUnsupportedOption {
+		t.Error("MaxFlow must reject the visitor", err)
+	}
+	criteria := algo.NewCriteriaGraph(1)
+	criteria.AddVertexes(2)
+	if _, err := criteria.ParetoPathsContext(ctx, 0, 1, visitor); err != algo.ErrUnsupportedOption {
+		t.Error("ParetoPaths must reject the visitor", err)
+	}
+}
+
+// weights records the exact weights vertexes are settled at
+type weights struct {
+	algo.BaseVisitor
+	settled map[int]float64
+}
+
+func (w weights) OnSettleWeight(vertex int, weight float64) algo.Action {
+	w.settled[vertex] = weight
+	return algo.Continue
+}
+
+func TestVisitorSearch(t *testing.T) {
+	graph := algo.NewGraph()
+	graph.AddVertexes(3)
+	graph.AddEdge(0, 1, 5, false)
+	graph.AddEdge(1, 2, 3, false)
+
+	// plain visitors get the weights rounded, infinity is out of range
+	a := arrivals{times: make(map[int]int)}
+	if _, err := algo.Search(graph, 0, algo.Widest, algo.WithVisitor(a)); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(a.times, map[int]int{0: algo.UndefDist, 1: 5, 2: 3}) {
+		t.Error("Wrong settled weights", a.times)
+	}
+
+	reliable, _ := algo.MostReliable(10)
+	w := weights{settled: make(map[int]float64)}
+	if _, err := algo.Search(graph, 0, reliable, algo.WithVisitor(w)); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(w.settled, map[int]float64{0: 1, 1: 0.5, 2: 0.15}) {
+		t.Error("Wrong settled weights", w.settled)
+	}
+
+	c := &counter{limit: 2}
+	path, err := algo.Search(graph, 0, algo.Shortest, algo.WithVisitor(c))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := path.Weight(2); ok {
+		t.Error("Search must stop")
+	}
+}
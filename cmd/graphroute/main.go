@@ -0,0 +1,299 @@
+// Command graphroute loads a graph and answers routing queries on it.
+//
+//	graphroute -graph roads.gr route 1 42
+//	graphroute -graph roads.csv dist 1
+//	graphroute -graph roads.json stats
+//
+// Without a query on the command line, queries are read from stdin one per
+// line. The graph format is taken from the file extension (.gr or .dimacs,
+// .csv, .json, .bin) unless -format says otherwise. Vertexes are numbered
+// as in the file: from 1 for DIMACS, from 0 for the other formats.
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	algo "github.com/octo47/gomisc/algo/dijkstra"
+)
+
+var errUsage = errors.New("usage: route <src> <dst> | dist <src> | stats")
+
+func main() {
+	graphFile := flag.String("graph", "", "graph file to load")
+	format := flag.String("format", "", "graph format: dimacs, csv, json or bin (default from extension)")
+	timing := flag.Bool("timing", true, "print query timing")
+	flag.Parse()
+
+	if *graphFile == "" {
+		fmt.Fprintln(os.Stderr, "graphroute: -graph is required")
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	start := time.Now()
+	kind, err := formatOf(*graphFile, *format)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "graphroute:", err)
+		os.Exit(2)
+	}
+	g, closer, err := load(*graphFile, kind)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "graphroute:", err)
+		os.Exit(1)
+	}
+	defer closer()
+
+	r := &runner{g: g, out: os.Stdout, timing: *timing}
+	if kind == "dimacs" {
+		r.base = 1
+	}
+	if r.timing {
+		fmt.Fprintf(r.out, "loaded %d vertexes in %v\n", g.VertexCount(), time.Since(start))
+	}
+
+	if flag.NArg() > 0 {
+		err = r.query(flag.Args())
+	} else {
+		err = r.serve(os.Stdin)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "graphroute:", err)
+		closer()
+		os.Exit(1)
+	}
+}
+
+// formatOf returns the given format, or the one the file name suggests
+func formatOf(name string, format string) (string, error) {
+	if format != "" {
+		return format, nil
+	}
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".gr", ".dimacs":
+		return "dimacs", nil
+	case ".csv":
+		return "csv", nil
+	case ".json":
+		return "json", nil
+	case ".bin":
+		return "bin", nil
+	}
+	return "", fmt.Errorf("can't guess format of %s, use -format", name)
+}
+
+// load reads the graph in the given format, see formatOf. The returned
+// func releases the graph.
+func load(name string, format string) (algo.Interface, func() error, error) {
+
+	if format == "bin" {
+		g, err := algo.OpenMapped(name)
+		if err != nil {
+			return nil, nil, err
+		}
+		return g, g.Close, nil
+	}
+
+	var read func(io.Reader) (*algo.Graph, error)
+	switch format {
+	case "dimacs":
+		read = algo.ReadDIMACS
+	case "csv":
+		read = algo.ReadCSV
+	case "json":
+		read = algo.ReadJSON
+	default:
+		return nil, nil, fmt.Errorf("unknown format %q", format)
+	}
+
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+	g, err := read(bufio.NewReader(f))
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %v", name, err)
+	}
+	return g, func() error { return nil }, nil
+}
+
+type runner struct {
+	g   algo.Interface
+	out io.Writer
+	// base is the number of the first vertex in queries and answers
+	base   int
+	timing bool
+}
+
+// serve answers queries read from r, one per line. A bad query is
+// reported and doesn't stop the following ones.
+func (r *runner) serve(in io.Reader) error {
+	scanner := bufio.NewScanner(in)
+	for scanner.Scan() {
+		args := strings.Fields(scanner.Text())
+		if len(args) == 0 || strings.HasPrefix(args[0], "#") {
+			continue
+		}
+		if err := r.query(args); err != nil {
+			fmt.Fprintln(r.out, "error:", err)
+		}
+	}
+	return scanner.Err()
+}
+
+func (r *runner) query(args []string) error {
+	start := time.Now()
+	var err error
+	switch args[0] {
+	case "route":
+		err = r.route(args[1:])
+	case "dist":
+		err = r.dist(args[1:])
+	case "stats":
+		err = r.stats(args[1:])
+	default:
+		err = errUsage
+	}
+	if err == nil && r.timing {
+		fmt.Fprintf(r.out, "took %v\n", time.Since(start))
+	}
+	return err
+}
+
+func (r *runner) route(args []string) error {
+	vertexes, err := r.vertexes(args, 2)
+	if err != nil {
+		return err
+	}
+	src, dst := vertexes[0], vertexes[1]
+	path, err := algo.Dijkstra(r.g, src)
+	if err != nil {
+		return err
+	}
+	cost := path.PathCost(dst)
+	if cost == algo.UndefDist {
+		fmt.Fprintf(r.out, "route %d -> %d: unreachable\n", src+r.base, dst+r.base)
+		return nil
+	}
+	hops := path.BuildPath(dst)
+	parts := make([]string, len(hops))
+	for i, v := range hops {
+		parts[len(hops)-1-i] = strconv.Itoa(v + r.base)
+	}
+	fmt.Fprintf(r.out, "route %d -> %d: cost %d, path %s\n", src+r.base, dst+r.base, cost, strings.Join(parts, " "))
+	return nil
+}
+
+func (r *runner) dist(args []string) error {
+	vertexes, err := r.vertexes(args, 1)
+	if err != nil {
+		return err
+	}
+	path, err := algo.Dijkstra(r.g, vertexes[0])
+	if err != nil {
+		return err
+	}
+	reached := 0
+	for v := 0; v < r.g.VertexCount(); v++ {
+		if cost := path.PathCost(v); cost != algo.UndefDist {
+			fmt.Fprintf(r.out, "%d\t%d\n", v+r.base, cost)
+			reached++
+		}
+	}
+	fmt.Fprintf(r.out, "dist %d: %d of %d vertexes reachable\n", vertexes[0]+r.base, reached, r.g.VertexCount())
+	return nil
+}
+
+func (r *runner) stats(args []string) error {
+	if len(args) != 0 {
+		return errUsage
+	}
+	n := r.g.VertexCount()
+	edges := 0
+	degrees := make(map[int]int)
+	weak := newUnionFind(n)
+	for v := 0; v < n; v++ {
+		degree := 0
+		r.g.Neighbors(v, func(e algo.Edge) bool {
+			degree++
+			weak.union(e.From, e.To)
+			return true
+		})
+		edges += degree
+		degrees[degree]++
+	}
+
+	fmt.Fprintf(r.out, "vertexes: %d\n", n)
+	fmt.Fprintf(r.out, "edges: %d\n", edges)
+	_, strong := algo.StronglyConnectedComponents(r.g)
+	fmt.Fprintf(r.out, "strongly connected components: %d\n", strong)
+	fmt.Fprintf(r.out, "weakly connected components: %d\n", weak.count)
+
+	keys := make([]int, 0, len(degrees))
+	for d := range degrees {
+		keys = append(keys, d)
+	}
+	sort.Ints(keys)
+	fmt.Fprintln(r.out, "out-degree distribution:")
+	for _, d := range keys {
+		fmt.Fprintf(r.out, "  %d\t%d\n", d, degrees[d])
+	}
+	return nil
+}
+
+// vertexes parses exactly count vertex arguments, numbered from base
+func (r *runner) vertexes(args []string, count int) ([]int, error) {
+	if len(args) != count {
+		return nil, errUsage
+	}
+	vertexes := make([]int, count)
+	for i, arg := range args {
+		v, err := strconv.Atoi(arg)
+		v -= r.base
+		if err != nil || v < 0 || v >= r.g.VertexCount() {
+			return nil, fmt.Errorf("bad vertex %q", arg)
+		}
+		vertexes[i] = v
+	}
+	return vertexes, nil
+}
+
+// unionFind counts the components left after joining vertexes
+type unionFind struct {
+	parent []int
+	count  int
+}
+
+func newUnionFind(n int) *unionFind {
+	parent := make([]int, n)
+	for i := range parent {
+		parent[i] = i
+	}
+	return &unionFind{parent: parent, count: n}
+}
+
+func (u *unionFind) find(v int) int {
+	for u.parent[v] != v {
+		u.parent[v] = u.parent[u.parent[v]]
+		v = u.parent[v]
+	}
+	return v
+}
+
+func (u *unionFind) union(a, b int) {
+	a, b = u.find(a), u.find(b)
+	if a != b {
+		u.parent[a] = b
+		u.count--
+	}
+}
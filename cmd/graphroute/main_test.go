@@ -0,0 +1,154 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	algo "github.com/octo47/gomisc/algo/dijkstra"
+)
+
+const sample = `c two islands
+p sp 5 4
+a 1 2 3
+a 2 3 4
+a 3 1 1
+a 4 5 2
+`
+
+func newRunner(t *testing.T) (*runner, *bytes.Buffer) {
+	g, err := algo.ReadDIMACS(strings.NewReader(sample))
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := new(bytes.Buffer)
+	return &runner{g: g, out: out, base: 1}, out
+}
+
+func TestRoute(t *testing.T) {
+	r, out := newRunner(t)
+	if err := r.query([]string{"route", "1", "3"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.query([]string{"route", "1", "5"}); err != nil {
+		t.Fatal(err)
+	}
+	want := "route 1 -> 3: cost 7, path 1 2 3\nroute 1 -> 5: unreachable\n"
+	if out.String() != want {
+		t.Errorf("Got %q, want %q", out.String(), want)
+	}
+}
+
+func TestDist(t *testing.T) {
+	r, out := newRunner(t)
+	if err := r.query([]string{"dist", "4"}); err != nil {
+		t.Fatal(err)
+	}
+	want := "4\t0\n5\t2\ndist 4: 2 of 5 vertexes reachable\n"
+	if out.String() != want {
+		t.Errorf("Got %q, want %q", out.String(), want)
+	}
+}
+
+func TestStats(t *testing.T) {
+	r, out := newRunner(t)
+	if err := r.query([]string{"stats"}); err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []string{
+		"vertexes: 5\n",
+		"edges: 4\n",
+		"strongly connected components: 3\n",
+		"weakly connected components: 2\n",
+		"  0\t1\n  1\t4\n",
+	} {
+		if !strings.Contains(out.String(), line) {
+			t.Errorf("Missing %q in\n%s", line, out.String())
+		}
+	}
+}
+
+func TestServe(t *testing.T) {
+	r, out := newRunner(t)
+	in := strings.NewReader("# comment\nroute 1 2\n\nroute 1 9\nroute 0 1\nfly\n")
+	if err := r.serve(in); err != nil {
+		t.Fatal(err)
+	}
+	want := "route 1 -> 2: cost 3, path 1 2\n" +
+		"error: bad vertex \"9\"\n" +
+		"error: bad vertex \"0\"\n" +
+		"error: " + errUsage.Error() + "\n"
+	if out.String() != want {
+		t.Errorf("Got %q, want %q", out.String(), want)
+	}
+}
+
+func TestZeroBased(t *testing.T) {
+	g, err := algo.ReadCSV(strings.NewReader("0,1,3\n1,2,4\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := new(bytes.Buffer)
+	r := &runner{g: g, out: out}
+	if err := r.query([]string{"route", "0", "2"}); err != nil {
+		t.Fatal(err)
+	}
+	if want := "route 0 -> 2: cost 7, path 0 1 2\n"; out.String() != want {
+		t.Errorf("Got %q, want %q", out.String(), want)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+
+	files := map[string]string{
+		"g.gr":   sample,
+		"g.csv":  "0,1,3\n1,2,4\n2,0,1\n3,4,2\n",
+		"g.json": `{"vertexes": 5, "edges": [{"from": 0, "to": 1, "cost": 3}]}`,
+	}
+	for name, body := range files {
+		path := filepath.Join(dir, name)
+		if err := ioutil.WriteFile(path, []byte(body), 0644); err != nil {
+			t.Fatal(err)
+		}
+		format, err := formatOf(path, "")
+		if err != nil {
+			t.Fatal(name, err)
+		}
+		g, closer, err := load(path, format)
+		if err != nil {
+			t.Fatal(name, err)
+		}
+		if g.VertexCount() != 5 {
+			t.Error(name, "wrong vertex count", g.VertexCount())
+		}
+		closer()
+	}
+
+	g, _ := algo.ReadDIMACS(strings.NewReader(sample))
+	var buf bytes.Buffer
+	if err := algo.WriteBinary(&buf, g, nil); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "g.bin")
+	if err := ioutil.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	mapped, closer, err := load(path, "bin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mapped.VertexCount() != 5 {
+		t.Error("Wrong vertex count", mapped.VertexCount())
+	}
+	closer()
+
+	if _, err := formatOf(filepath.Join(dir, "g.txt"), ""); err == nil {
+		t.Error("Unknown extension accepted")
+	}
+	if _, _, err := load(path, "xml"); err == nil {
+		t.Error("Unknown format accepted")
+	}
+}
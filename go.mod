@@ -1,3 +1,3 @@
 module github.com/octo47/gomisc
 
-go 1.13
+go 1.18